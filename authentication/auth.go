@@ -1,12 +1,14 @@
 package authentication
 
 import (
+	"context"
+	"crypto/subtle"
 	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	ae "github.com/piyushkumar96/app-error"
-	"github.com/piyushkumar96/common-middlewares/context"
+	cx "github.com/piyushkumar96/common-middlewares/context"
 )
 
 // AuthConfig configures the Auth middleware
@@ -17,14 +19,24 @@ type AuthConfig struct {
 // Auth returns a gin middleware that validates the request against AuthConfig.Token (e.g. Bearer or static token in Authorization header).
 func Auth(authConfig *AuthConfig) gin.HandlerFunc {
 	return func(gc *gin.Context) {
-		headers := gc.Request.Header[string(context.HeaderAuthorization)]
-		if len(headers) == 0 || authConfig.Token != headers[0] {
-			ctx := context.GetRequestContext(gc)
-			appErr := ae.GetAppErr(ctx, errors.New(ErrUnauthorized.Message), ErrUnauthorized, http.StatusUnauthorized)
-			context.RespondJSON(gc, http.StatusUnauthorized, context.MessageFailure(appErr.GetMsg()))
-			gc.Abort()
+		var authorization string
+		if headers := gc.Request.Header[string(cx.HeaderAuthorization)]; len(headers) > 0 {
+			authorization = headers[0]
+		}
+		ctx := cx.GetRequestContext(gc)
+		if appErr := authConfig.Validate(ctx, authorization); appErr != nil {
+			cx.RespondError(gc, appErr)
 			return
 		}
 		gc.Next()
 	}
 }
+
+// Validate checks an Authorization value against AuthConfig.Token. It is shared by the gin middleware and
+// the gRPC interceptors, and returns ErrUnauthorized (401) on mismatch. The comparison is constant-time.
+func (authConfig *AuthConfig) Validate(ctx context.Context, authorization string) *ae.AppError {
+	if authorization == "" || subtle.ConstantTimeCompare([]byte(authConfig.Token), []byte(authorization)) != 1 {
+		return ae.GetAppErr(ctx, errors.New(ErrUnauthorized.Message), ErrUnauthorized, http.StatusUnauthorized)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package context
+
+import (
+	"strconv"
+	"strings"
+)
+
+// acceptRange is one media range of an Accept header with its quality.
+type acceptRange struct {
+	mediaType string
+	q         float64
+}
+
+// parseAccept parses an Accept header; ranges without a valid q get 1, invalid entries are skipped.
+func parseAccept(header string) []acceptRange {
+	var ranges []acceptRange
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		mediaType := strings.ToLower(strings.TrimSpace(fields[0]))
+		if mediaType == "" || !strings.Contains(mediaType, "/") {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if ok && strings.EqualFold(strings.TrimSpace(key), "q") {
+				if parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil && parsed >= 0 && parsed <= 1 {
+					q = parsed
+				}
+			}
+		}
+		ranges = append(ranges, acceptRange{mediaType: mediaType, q: q})
+	}
+	return ranges
+}
+
+// acceptQuality returns the quality of mediaType under ranges using the most specific matching range
+// (type/subtype over type/* over */*), and whether any range matched explicitly (type/subtype).
+func acceptQuality(ranges []acceptRange, mediaType string) (q float64, explicit bool) {
+	mainType, _, _ := strings.Cut(mediaType, "/")
+	specificity := -1
+	for _, r := range ranges {
+		level := -1
+		switch r.mediaType {
+		case mediaType:
+			level = 2
+		case mainType + "/*":
+			level = 1
+		case "*/*":
+			level = 0
+		}
+		if level > specificity {
+			specificity, q = level, r.q
+		}
+	}
+	return q, specificity == 2
+}
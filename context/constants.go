@@ -1,13 +1,31 @@
 package context
 
+import "regexp"
+
 const (
 	CtxKey          = "ctx"
 	CtxMetaKey      = "ctxMeta"
 	ResponseMetaKey = "ResponseMeta"
 	TraceMetaKey    = "TraceMeta"
 	ReqIDKey        = "request_id"
+	CSPNonceKey     = "cspNonce"
+	BaggageKey      = "Baggage"
+	// SamplingDecisionKey holds the trace package's *SamplingDecision.
+	SamplingDecisionKey = "SamplingDecision"
+	// ErrorFormatKey holds the ErrorFormat of a route (see WithErrorFormat).
+	ErrorFormatKey = "ErrorFormat"
+)
+
+// Well-known identifier mapping keys (see SetIdentifier).
+const (
+	IdentifierOrderID   = "order_id"
+	IdentifierAccountID = "account_id"
+	IdentifierJobID     = "job_id"
 )
 
+// BaggageFieldPrefix prefixes promoted baggage keys in LogFields, e.g. "baggage.tenant".
+const BaggageFieldPrefix = "baggage."
+
 // Trace separator used in AddTrace
 const UnderScore = "_"
 
@@ -15,15 +33,42 @@ type TRequestHeaderKey string
 
 const (
 	HeaderAuthorization TRequestHeaderKey = "Authorization"
+	HeaderRequestID     TRequestHeaderKey = "X-Request-ID"
 	HeaderResponseReqID TRequestHeaderKey = "req-id"
 	HeaderContentType   TRequestHeaderKey = "Content-Type"
 	HeaderAccountID     TRequestHeaderKey = "x-account-id"
 	HeaderUserIDKey     TRequestHeaderKey = "x-user-id"
 	HeaderAPIKey        TRequestHeaderKey = "x-api-key"
+	HeaderVary          TRequestHeaderKey = "Vary"
 )
 
+const (
+	defaultRequestIDMaxLength = 128
+	crockfordAlphabet         = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+)
+
+// requestIDPattern is the default charset for inbound request IDs; it excludes whitespace, quotes and control
+// characters so IDs cannot inject into logs.
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]+$`)
+
 type TResponseContentType string
 
 const (
-	ApplicationJSON TResponseContentType = "application/json"
+	ApplicationJSON        TResponseContentType = "application/json"
+	ApplicationProblemJSON TResponseContentType = "application/problem+json"
+	ApplicationXML         TResponseContentType = "application/xml"
+	ApplicationMsgPack     TResponseContentType = "application/msgpack"
+	ApplicationXMsgPack    TResponseContentType = "application/x-msgpack"
+	ApplicationCBOR        TResponseContentType = "application/cbor"
+	ApplicationProtobuf    TResponseContentType = "application/x-protobuf"
+)
+
+// problemTypeBlank is the RFC 9457 default problem type, used when no type base URI is configured.
+const problemTypeBlank = "about:blank"
+
+// Problem titles used when http.StatusText does not know the status code.
+const (
+	problemTitleClientError = "Client Error"
+	problemTitleServerError = "Server Error"
+	problemTitleUnknown     = "Unknown Error"
 )
@@ -0,0 +1,107 @@
+package context
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"reflect"
+
+	"github.com/ugorji/go/codec"
+	"google.golang.org/protobuf/proto"
+)
+
+// Encoder renders response bodies in one media type for Respond.
+type Encoder interface {
+	// MediaType is matched against the Accept header and sent as Content-Type.
+	MediaType() string
+	// CanEncode reports whether body can be rendered at all (e.g. protobuf needs a proto.Message).
+	CanEncode(body interface{}) bool
+	Encode(w io.Writer, body interface{}) error
+}
+
+var (
+	msgpackHandle = &codec.MsgpackHandle{}
+	cborHandle    = &codec.CborHandle{}
+)
+
+// JSONEncoder renders application/json.
+type JSONEncoder struct{}
+
+func (JSONEncoder) MediaType() string { return string(ApplicationJSON) }
+
+func (JSONEncoder) CanEncode(interface{}) bool { return true }
+
+func (JSONEncoder) Encode(w io.Writer, body interface{}) error {
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(encoded)
+	return err
+}
+
+// XMLEncoder renders application/xml. Maps are not supported by encoding/xml; other bodies it cannot marshal
+// fail to encode and Respond falls back to the next acceptable encoder.
+type XMLEncoder struct{}
+
+func (XMLEncoder) MediaType() string { return string(ApplicationXML) }
+
+func (XMLEncoder) CanEncode(body interface{}) bool {
+	value := reflect.ValueOf(body)
+	for value.Kind() == reflect.Pointer {
+		value = value.Elem()
+	}
+	return value.IsValid() && value.Kind() != reflect.Map
+}
+
+func (XMLEncoder) Encode(w io.Writer, body interface{}) error {
+	return xml.NewEncoder(w).Encode(body)
+}
+
+// MsgPackEncoder renders MessagePack under MediaTypeName (application/msgpack when empty).
+type MsgPackEncoder struct {
+	MediaTypeName string
+}
+
+func (e MsgPackEncoder) MediaType() string {
+	if e.MediaTypeName == "" {
+		return string(ApplicationMsgPack)
+	}
+	return e.MediaTypeName
+}
+
+func (MsgPackEncoder) CanEncode(interface{}) bool { return true }
+
+func (MsgPackEncoder) Encode(w io.Writer, body interface{}) error {
+	return codec.NewEncoder(w, msgpackHandle).Encode(body)
+}
+
+// CBOREncoder renders application/cbor.
+type CBOREncoder struct{}
+
+func (CBOREncoder) MediaType() string { return string(ApplicationCBOR) }
+
+func (CBOREncoder) CanEncode(interface{}) bool { return true }
+
+func (CBOREncoder) Encode(w io.Writer, body interface{}) error {
+	return codec.NewEncoder(w, cborHandle).Encode(body)
+}
+
+// ProtobufEncoder renders application/x-protobuf for bodies that are proto messages.
+type ProtobufEncoder struct{}
+
+func (ProtobufEncoder) MediaType() string { return string(ApplicationProtobuf) }
+
+func (ProtobufEncoder) CanEncode(body interface{}) bool {
+	_, ok := body.(proto.Message)
+	return ok
+}
+
+func (ProtobufEncoder) Encode(w io.Writer, body interface{}) error {
+	encoded, err := proto.Marshal(body.(proto.Message))
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(encoded)
+	return err
+}
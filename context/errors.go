@@ -14,4 +14,9 @@ var (
 		"ERR_CTX_1001",
 		"error while writing response",
 		false)
+	// OnNotAcceptable is returned by Respond when no registered encoder matches the Accept header.
+	OnNotAcceptable = ae.GetCustomErr(
+		"ERR_CTX_1002",
+		"none of the accepted media types is supported",
+		false)
 )
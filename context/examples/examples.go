@@ -3,14 +3,22 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	ae "github.com/piyushkumar96/app-error"
 	"github.com/piyushkumar96/common-middlewares/context"
 )
 
+// item is rendered by /negotiate in the media type the client accepts.
+type item struct {
+	Name     string `json:"name" xml:"name"`
+	Quantity int    `json:"quantity" xml:"quantity"`
+}
+
 func main() {
 	gin.SetMode(gin.ReleaseMode)
 	r := gin.New()
@@ -26,11 +34,33 @@ func main() {
 		c.JSON(http.StatusOK, gin.H{"message": "pong", "request_id": reqID})
 	})
 
+	// Standard envelope: {"success":true,"data":[...],"meta":{...},"request_id":"..."}
+	r.GET("/items", func(c *gin.Context) {
+		context.RespondSuccess(c, http.StatusOK, []string{"a", "b"}, &context.PageMeta{Page: 1, PageSize: 2, Total: 10})
+	})
+
+	// Standard error envelope: {"success":false,"message":"...","error":{"code":"...","message":"...","details":[...]},"request_id":"..."}
 	r.GET("/fail", func(c *gin.Context) {
-		context.RespondJSON(c, http.StatusBadRequest, context.MessageFailure("something went wrong"))
+		ctx := context.GetRequestContext(c)
+		appErr := ae.GetAppErr(ctx, errors.New("quantity must be positive"), context.OnRequestFailure, http.StatusBadRequest)
+		context.RespondError(c, appErr, context.ErrorDetail{Field: "quantity", Message: "must be positive"})
+	})
+
+	// Same error as application/problem+json (RFC 9457); without WithErrorFormat, clients can ask for it through
+	// Accept when ErrorFormatConfig.NegotiateAccept is set.
+	context.SetErrorFormatConfig(&context.ErrorFormatConfig{NegotiateAccept: true, ProblemTypeBaseURI: "https://errors.example.com/"})
+	r.GET("/problem", context.WithErrorFormat(context.ErrorFormatProblem), func(c *gin.Context) {
+		ctx := context.GetRequestContext(c)
+		appErr := ae.GetAppErr(ctx, errors.New("quantity must be positive"), context.OnRequestFailure, http.StatusBadRequest)
+		context.RespondError(c, appErr, context.ErrorDetail{Field: "quantity", Message: "must be positive"})
+	})
+
+	// Content negotiation: JSON by default, XML / MessagePack / CBOR (or protobuf for proto messages) per Accept.
+	r.GET("/negotiate", func(c *gin.Context) {
+		context.Respond(c, http.StatusOK, &item{Name: "widget", Quantity: 3})
 	})
 
-	fmt.Println("Context example: GET http://localhost:8083/ping or /fail")
+	fmt.Println("Context example: GET http://localhost:8083/ping, /items, /fail, /problem or /negotiate")
 	if err := r.Run(":8083"); err != nil {
 		log.Fatal(err)
 	}
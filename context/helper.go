@@ -2,9 +2,6 @@ package context
 
 import (
 	"context"
-	"crypto/rand"
-	"encoding/hex"
-	"fmt"
 	"strings"
 	"time"
 
@@ -12,28 +9,29 @@ import (
 	"github.com/piyushkumar96/app-monitoring/interfaces"
 )
 
-func newRequestID() string {
-	b := make([]byte, 8)
-	if _, err := rand.Read(b); err != nil {
-		return fmt.Sprintf("%d", time.Now().UnixNano())
-	}
-	return hex.EncodeToString(b) + fmt.Sprintf("%d", time.Now().UnixNano())
-}
-
 // InitRequestContext creates a context with request ID and stores it in gin.
 // Use GetRequestContext to retrieve it. Call this from Trace middleware.
 func InitRequestContext(gc *gin.Context) context.Context {
-	reqID := gc.GetHeader("X-Request-ID")
-	if reqID == "" {
-		reqID = newRequestID()
-	}
-	gc.Header("X-Request-ID", reqID)
+	reqID := ResolveRequestID(gc)
 	ctx := gc.Request.Context()
 	ctx = context.WithValue(ctx, ReqIDKey, reqID)
-	gc.Set(CtxKey, ctx)
+	SetRequestContext(gc, ctx)
 	return ctx
 }
 
+// SetRequestContext stores ctx in gin and on gc.Request, so GetRequestContext and gc.Request.Context()
+// return the same context. ctx should derive from gc.Request.Context() to keep its cancellation.
+func SetRequestContext(gc *gin.Context, ctx context.Context) {
+	gc.Set(CtxKey, ctx)
+	gc.Request = gc.Request.WithContext(ctx)
+}
+
+// Detach returns a context with the values of ctx (request ID, metas, trace span) but without its cancellation
+// and deadline, for background work that must outlive the request.
+func Detach(ctx context.Context) context.Context {
+	return context.WithoutCancel(ctx)
+}
+
 // GetRequestContext returns the request context from gin, or the request's context.
 func GetRequestContext(gc *gin.Context) context.Context {
 	if v, ok := gc.Get(CtxKey); ok {
@@ -44,18 +42,14 @@ func GetRequestContext(gc *gin.Context) context.Context {
 	return gc.Request.Context()
 }
 
+// InitContextMeta builds the CtxMeta of a request; the request ID comes from ResolveRequestID.
 func InitContextMeta(gc *gin.Context, body string) (string, *CtxMeta) {
-	reqID := gc.GetHeader("X-Request-ID")
-	if reqID == "" {
-		reqID = newRequestID()
-		gc.Header("X-Request-ID", reqID)
-	}
+	reqID := ResolveRequestID(gc)
 	ctxMeta := CtxMeta{
 		DeploymentID: gc.GetHeader("x-deployment-id"),
 		UserID:       gc.GetHeader("x-user-id"),
 		TraceParent:  gc.GetHeader("traceparent"),
 		TraceState:   gc.GetHeader("tracestate"),
-		TraceID:      fmt.Sprintf("%s:%s", gc.GetHeader("traceparent"), gc.GetHeader("tracestate")),
 		ReqID:        reqID,
 		Path:         gc.Request.RequestURI,
 		Body:         body,
@@ -71,10 +65,10 @@ func InitResponseMeta(gc *gin.Context, appMetrics interfaces.AppMetricsInterface
 }
 
 func InitTraceMeta() (string, *TraceMeta) {
-	return TraceMetaKey, &TraceMeta{Trace: make([]string, 0)}
+	return TraceMetaKey, &TraceMeta{Trace: make([]string, 0), IdentifierMappings: make(map[string]interface{})}
 }
 
-// GetContext returns the context from gin (with optional body update to CtxMeta). If not set, returns Background and sets it.
+// GetContext returns the context from gin (with optional body update to CtxMeta). If not set, returns the request's context and sets it.
 func GetContext(gc *gin.Context, body string) context.Context {
 	var ctx context.Context
 	ctxInterface, exists := gc.Get(CtxKey)
@@ -83,7 +77,7 @@ func GetContext(gc *gin.Context, body string) context.Context {
 		ctxMeta := GetContextMeta(ctx)
 		ctxMeta.Body = body
 	} else {
-		ctx = context.Background()
+		ctx = gc.Request.Context()
 		gc.Set(CtxKey, ctx)
 	}
 	return ctx
@@ -125,7 +119,7 @@ func AddTrace(ctx context.Context, msg ...string) *TraceMeta {
 	if !ok {
 		return nil
 	}
-	traceMeta.Trace = append(traceMeta.Trace, strings.Join(msg, UnderScore))
+	traceMeta.AddTrace(strings.Join(msg, UnderScore))
 	return traceMeta
 }
 
@@ -138,7 +132,7 @@ func AddTraceLog(ctx context.Context, errorMsg string) *TraceMeta {
 	if !ok {
 		return nil
 	}
-	traceMeta.Error = append(traceMeta.Error, errorMsg)
+	traceMeta.AddError(errorMsg)
 	return traceMeta
 }
 
@@ -148,6 +142,8 @@ func RespondJSON(gc *gin.Context, statusCode int, body interface{}) {
 }
 
 // MessageFailure returns a simple failure map for JSON responses.
+//
+// Deprecated: use RespondError (or NewFailureResponse), which adds the error code and request ID.
 func MessageFailure(message string) map[string]interface{} {
 	return map[string]interface{}{
 		"success": false,
@@ -155,12 +151,12 @@ func MessageFailure(message string) map[string]interface{} {
 	}
 }
 
-// GetRequestID returns the request ID from context or empty string.
+// GetRequestID returns the request ID from context (ReqIDKey, else CtxMeta.ReqID) or empty string.
 func GetRequestID(ctx context.Context) string {
 	if v := ctx.Value(ReqIDKey); v != nil {
 		if s, ok := v.(string); ok {
 			return s
 		}
 	}
-	return ""
+	return GetContextMeta(ctx).ReqID
 }
@@ -0,0 +1,83 @@
+package context
+
+import (
+	"context"
+	"maps"
+	"slices"
+)
+
+// SetIdentifier attaches a business identifier (e.g. IdentifierOrderID) to the request in ctx, so the trace
+// summary, exported records and LogFields carry it. It is a no-op when ctx has no TraceMeta or value is empty.
+func SetIdentifier(ctx context.Context, key, value string) {
+	if value == "" {
+		return
+	}
+	if traceMeta := traceMetaFrom(ctx); traceMeta != nil {
+		traceMeta.SetIdentifier(key, value)
+	}
+}
+
+// SetOrderID attaches an order ID to the request in ctx.
+func SetOrderID(ctx context.Context, orderID string) {
+	SetIdentifier(ctx, IdentifierOrderID, orderID)
+}
+
+// SetAccountID attaches an account ID to the request in ctx.
+func SetAccountID(ctx context.Context, accountID string) {
+	SetIdentifier(ctx, IdentifierAccountID, accountID)
+}
+
+// SetJobID attaches a job ID to the request in ctx.
+func SetJobID(ctx context.Context, jobID string) {
+	SetIdentifier(ctx, IdentifierJobID, jobID)
+}
+
+// GetIdentifier returns the identifier stored under key for the request in ctx, or empty string.
+func GetIdentifier(ctx context.Context, key string) string {
+	traceMeta := traceMetaFrom(ctx)
+	if traceMeta == nil {
+		return ""
+	}
+	value, _ := traceMeta.GetIdentifier(key)
+	s, _ := value.(string)
+	return s
+}
+
+// GetIdentifiers returns a copy of all identifiers of the request in ctx.
+func GetIdentifiers(ctx context.Context) map[string]interface{} {
+	traceMeta := traceMetaFrom(ctx)
+	if traceMeta == nil {
+		return nil
+	}
+	return traceMeta.Identifiers()
+}
+
+// LogFields returns the request ID, the identifiers and the promoted baggage (CtxMeta.Baggage) of the request
+// in ctx as logger key/value pairs, e.g. l.Logger.Info("order placed", cx.LogFields(ctx)...).
+func LogFields(ctx context.Context) []interface{} {
+	fields := append([]interface{}{ReqIDKey, GetRequestID(ctx)}, IdentifierFields(GetIdentifiers(ctx))...)
+	return append(fields, BaggageFields(GetContextMeta(ctx).Baggage)...)
+}
+
+// IdentifierFields flattens identifier mappings into logger key/value pairs sorted by key.
+// Keys clashing with ReqIDKey are skipped.
+func IdentifierFields(mappings map[string]interface{}) []interface{} {
+	fields := make([]interface{}, 0, 2*len(mappings))
+	for _, key := range slices.Sorted(maps.Keys(mappings)) {
+		if key == ReqIDKey {
+			continue
+		}
+		fields = append(fields, key, mappings[key])
+	}
+	return fields
+}
+
+// BaggageFields flattens promoted baggage into logger key/value pairs sorted by key, each key prefixed with
+// BaggageFieldPrefix so it cannot clash with identifiers.
+func BaggageFields(baggage map[string]string) []interface{} {
+	fields := make([]interface{}, 0, 2*len(baggage))
+	for _, key := range slices.Sorted(maps.Keys(baggage)) {
+		fields = append(fields, BaggageFieldPrefix+key, baggage[key])
+	}
+	return fields
+}
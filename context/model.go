@@ -2,6 +2,7 @@ package context
 
 import (
 	"net/url"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -11,15 +12,23 @@ import (
 type CtxMeta struct {
 	DeploymentID string
 	UserID       string
-	TraceParent  string
-	TraceState   string
+	// TraceParent is the W3C traceparent of this hop (to propagate downstream); TraceState is the validated inbound tracestate.
+	TraceParent string
+	TraceState  string
+	// TraceID (32 hex), SpanID (16 hex, this hop), ParentSpanID (16 hex, caller's span; empty for a root) and Sampled
+	// are filled by trace.Trace from the inbound traceparent.
 	TraceID      string
-	ReqID        string
-	Path         string
-	Body         string
-	UA           string
-	QP           url.Values
-	Time         time.Time
+	SpanID       string
+	ParentSpanID string
+	Sampled      bool
+	// Baggage holds the W3C baggage entries promoted by trace.TraceConfig.BaggagePromotedKeys.
+	Baggage map[string]string
+	ReqID   string
+	Path    string
+	Body    string
+	UA      string
+	QP      url.Values
+	Time    time.Time
 }
 
 // ResponseMeta holds response writer and optional app metrics from piyushkumar96/app-monitoring.
@@ -28,8 +37,23 @@ type ResponseMeta struct {
 	AppMetrics interfaces.AppMetricsInterface
 }
 
+// TraceMeta collects trace and error entries for a request. It is shared by pointer and safe for concurrent use
+// through its methods (AddTrace, AddError, SetIdentifier, Snapshot, Merge, ...); the fields must not be read or
+// written directly while other goroutines may use it.
 type TraceMeta struct {
-	Trace              []string
-	Error              []string
+	mu sync.RWMutex
+	// events are the structured entries (see AddEvent); Trace and Error are their legacy string view.
+	events []TraceEvent
+	// skipVerbose drops debug and info events for unsampled requests (see SetRecording).
+	skipVerbose bool
+	// Trace is kept in sync with the events under the lock.
+	//
+	// Deprecated: reading it races with concurrent writers; use Traces or Snapshot.
+	Trace []string
+	// Error is kept in sync with the events under the lock.
+	//
+	// Deprecated: reading it races with concurrent writers; use Errors or Snapshot.
+	Error []string
+	// Deprecated: reading or writing it races with concurrent writers; use SetIdentifier, GetIdentifier or Identifiers.
 	IdentifierMappings map[string]interface{}
 }
@@ -0,0 +1,172 @@
+package context
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	ae "github.com/piyushkumar96/app-error"
+)
+
+// EncoderRegistry holds the encoders Respond negotiates between; registration order breaks quality ties.
+type EncoderRegistry struct {
+	mu       sync.RWMutex
+	encoders []Encoder
+}
+
+// NewEncoderRegistry returns a registry holding encoders.
+func NewEncoderRegistry(encoders ...Encoder) *EncoderRegistry {
+	r := &EncoderRegistry{}
+	for _, encoder := range encoders {
+		r.Register(encoder)
+	}
+	return r
+}
+
+// NewDefaultEncoderRegistry returns a registry with JSON (preferred), XML, MessagePack, CBOR and protobuf.
+func NewDefaultEncoderRegistry() *EncoderRegistry {
+	return NewEncoderRegistry(
+		JSONEncoder{},
+		XMLEncoder{},
+		MsgPackEncoder{},
+		MsgPackEncoder{MediaTypeName: string(ApplicationXMsgPack)},
+		CBOREncoder{},
+		ProtobufEncoder{},
+	)
+}
+
+var defaultEncoders = NewDefaultEncoderRegistry()
+
+// RegisterEncoder adds encoder to the registry used by Respond.
+func RegisterEncoder(encoder Encoder) {
+	defaultEncoders.Register(encoder)
+}
+
+// Register adds encoder, replacing an encoder with the same media type in place.
+func (r *EncoderRegistry) Register(encoder Encoder) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	mediaType := strings.ToLower(encoder.MediaType())
+	for i, existing := range r.encoders {
+		if strings.ToLower(existing.MediaType()) == mediaType {
+			r.encoders[i] = encoder
+			return
+		}
+	}
+	r.encoders = append(r.encoders, encoder)
+}
+
+// MediaTypes returns the media types of the registered encoders that can render body.
+func (r *EncoderRegistry) MediaTypes(body interface{}) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	mediaTypes := make([]string, 0, len(r.encoders))
+	for _, encoder := range r.encoders {
+		if encoder.CanEncode(body) {
+			mediaTypes = append(mediaTypes, encoder.MediaType())
+		}
+	}
+	return mediaTypes
+}
+
+// Negotiate returns the encoders able to render body that accept allows, most preferred first: higher quality,
+// then explicit type/subtype matches, then registration order. An empty accept allows everything.
+func (r *EncoderRegistry) Negotiate(accept string, body interface{}) []Encoder {
+	if strings.TrimSpace(accept) == "" {
+		accept = "*/*"
+	}
+	ranges := parseAccept(accept)
+	type candidate struct {
+		encoder  Encoder
+		q        float64
+		explicit bool
+	}
+	r.mu.RLock()
+	candidates := make([]candidate, 0, len(r.encoders))
+	for _, encoder := range r.encoders {
+		if !encoder.CanEncode(body) {
+			continue
+		}
+		if q, explicit := acceptQuality(ranges, strings.ToLower(encoder.MediaType())); q > 0 {
+			candidates = append(candidates, candidate{encoder: encoder, q: q, explicit: explicit})
+		}
+	}
+	r.mu.RUnlock()
+
+	slices.SortStableFunc(candidates, func(a, b candidate) int {
+		switch {
+		case a.q != b.q:
+			if a.q > b.q {
+				return -1
+			}
+			return 1
+		case a.explicit != b.explicit:
+			if a.explicit {
+				return -1
+			}
+			return 1
+		}
+		return 0
+	})
+	encoders := make([]Encoder, 0, len(candidates))
+	for _, c := range candidates {
+		encoders = append(encoders, c.encoder)
+	}
+	return encoders
+}
+
+// Respond writes body in the media type negotiated from the Accept header (see RegisterEncoder) and aborts with
+// the given status code. When no encoder is acceptable it responds 406 with one Accept detail listing the supported media types.
+func Respond(gc *gin.Context, statusCode int, body interface{}) {
+	RespondWithRegistry(gc, defaultEncoders, statusCode, body)
+}
+
+// RespondWithRegistry is Respond with a specific encoder registry.
+func RespondWithRegistry(gc *gin.Context, registry *EncoderRegistry, statusCode int, body interface{}) {
+	addVary(gc.Writer.Header(), "Accept")
+	ctx := GetRequestContext(gc)
+	encoders := registry.Negotiate(gc.GetHeader("Accept"), body)
+	if len(encoders) == 0 {
+		supported := "supported media types: " + strings.Join(registry.MediaTypes(body), ", ")
+		appErr := ae.GetAppErr(ctx, errors.New(OnNotAcceptable.Message+"; "+supported), OnNotAcceptable, http.StatusNotAcceptable)
+		RespondError(gc, appErr, ErrorDetail{Field: "Accept", Message: supported})
+		return
+	}
+
+	var buf bytes.Buffer
+	for _, encoder := range encoders {
+		buf.Reset()
+		err := encoder.Encode(&buf, body)
+		if err == nil {
+			gc.Header(string(HeaderContentType), encoder.MediaType())
+			gc.Status(statusCode)
+			_, _ = gc.Writer.Write(buf.Bytes())
+			gc.Abort()
+			return
+		}
+		AddTraceLog(ctx, err.Error())
+	}
+	appErr := ae.GetAppErr(ctx, errors.New(OnResponseWriteError.Message), OnResponseWriteError, http.StatusInternalServerError)
+	RespondError(gc, appErr)
+}
+
+// addVary merges value into the Vary header as one comma separated line, unless it (or "*") is already listed.
+func addVary(header http.Header, value string) {
+	values := make([]string, 0)
+	for _, line := range header.Values(string(HeaderVary)) {
+		for _, item := range strings.Split(line, ",") {
+			item = strings.TrimSpace(item)
+			if item == "*" || strings.EqualFold(item, value) {
+				return
+			}
+			if item != "" {
+				values = append(values, item)
+			}
+		}
+	}
+	header.Set(string(HeaderVary), strings.Join(append(values, value), ", "))
+}
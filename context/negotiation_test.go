@@ -0,0 +1,239 @@
+package context
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+	"testing"
+
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// testEncoder is a configurable Encoder; it writes its media type as the body unless err is set.
+type testEncoder struct {
+	mediaType string
+	err       error
+}
+
+func (e testEncoder) MediaType() string { return e.mediaType }
+
+func (e testEncoder) CanEncode(interface{}) bool { return true }
+
+func (e testEncoder) Encode(w io.Writer, _ interface{}) error {
+	if e.err != nil {
+		return e.err
+	}
+	_, err := io.WriteString(w, e.mediaType)
+	return err
+}
+
+type testItem struct {
+	ID   string `json:"id" xml:"id"`
+	Name string `json:"name" xml:"name"`
+}
+
+func mediaTypesOf(encoders []Encoder) []string {
+	mediaTypes := make([]string, 0, len(encoders))
+	for _, encoder := range encoders {
+		mediaTypes = append(mediaTypes, encoder.MediaType())
+	}
+	return mediaTypes
+}
+
+func TestNegotiate(t *testing.T) {
+	item := testItem{ID: "1", Name: "one"}
+	textRegistry := NewEncoderRegistry(
+		testEncoder{mediaType: "text/plain"},
+		testEncoder{mediaType: "application/json"},
+		testEncoder{mediaType: "text/csv"},
+	)
+	tests := []struct {
+		name     string
+		registry *EncoderRegistry
+		accept   string
+		body     interface{}
+		want     []string
+	}{
+		{
+			name:   "empty accept allows all in registration order",
+			accept: "",
+			body:   item,
+			want:   []string{"application/json", "application/xml", "application/msgpack", "application/x-msgpack", "application/cbor"},
+		},
+		{
+			name:   "q=0 excludes an explicit type",
+			accept: "application/json;q=0, */*",
+			body:   item,
+			want:   []string{"application/xml", "application/msgpack", "application/x-msgpack", "application/cbor"},
+		},
+		{
+			name:   "q=0 wildcard leaves only explicit types",
+			accept: "application/cbor, */*;q=0",
+			body:   item,
+			want:   []string{"application/cbor"},
+		},
+		{
+			name:   "higher quality first",
+			accept: "application/json;q=0.5, application/xml;q=0.9, application/cbor;q=0.7",
+			body:   item,
+			want:   []string{"application/xml", "application/cbor", "application/json"},
+		},
+		{
+			name:     "type wildcard is more specific than any",
+			registry: textRegistry,
+			accept:   "*/*;q=0.2, text/*;q=0.6",
+			body:     item,
+			want:     []string{"text/plain", "text/csv", "application/json"},
+		},
+		{
+			name:     "explicit match wins a quality tie",
+			registry: textRegistry,
+			accept:   "text/*, text/csv",
+			body:     item,
+			want:     []string{"text/csv", "text/plain"},
+		},
+		{
+			name:   "explicit match wins a tie against any",
+			accept: "*/*, application/cbor",
+			body:   item,
+			want:   []string{"application/cbor", "application/json", "application/xml", "application/msgpack", "application/x-msgpack"},
+		},
+		{
+			name:   "xml cannot encode maps",
+			accept: "application/xml",
+			body:   map[string]string{"id": "1"},
+			want:   []string{},
+		},
+		{
+			name:   "xml falls back to json for maps",
+			accept: "application/xml, application/json;q=0.5",
+			body:   map[string]string{"id": "1"},
+			want:   []string{"application/json"},
+		},
+		{
+			name:   "protobuf encodes proto messages",
+			accept: "application/x-protobuf, application/json;q=0.5",
+			body:   wrapperspb.String("one"),
+			want:   []string{"application/x-protobuf", "application/json"},
+		},
+		{
+			name:   "protobuf refuses other bodies",
+			accept: "application/x-protobuf",
+			body:   item,
+			want:   []string{},
+		},
+		{
+			name:   "media types are case-insensitive",
+			accept: "Application/CBOR",
+			body:   item,
+			want:   []string{"application/cbor"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := tt.registry
+			if registry == nil {
+				registry = NewDefaultEncoderRegistry()
+			}
+			got := mediaTypesOf(registry.Negotiate(tt.accept, tt.body))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Negotiate(%q) = %v, want %v", tt.accept, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncoderRegistryRegisterReplaces(t *testing.T) {
+	registry := NewDefaultEncoderRegistry()
+	before := registry.MediaTypes(testItem{})
+
+	replacement := testEncoder{mediaType: "Application/JSON"}
+	registry.Register(replacement)
+	after := registry.MediaTypes(testItem{})
+	if len(after) != len(before) {
+		t.Fatalf("media types after replacement = %v, want %d entries", after, len(before))
+	}
+	encoders := registry.Negotiate("application/json", testItem{})
+	if len(encoders) != 1 || encoders[0] != replacement {
+		t.Fatalf("Negotiate after replacement = %v, want the replacement only", mediaTypesOf(encoders))
+	}
+	if first := registry.Negotiate("", testItem{})[0]; first != replacement {
+		t.Errorf("replacement moved to %s, want it in place of JSON", first.MediaType())
+	}
+
+	registry.Register(testEncoder{mediaType: "text/plain"})
+	if got := registry.MediaTypes(testItem{}); got[len(got)-1] != "text/plain" {
+		t.Errorf("new media type not appended: %v", got)
+	}
+}
+
+func TestRespondWithRegistryNotAcceptable(t *testing.T) {
+	registry := NewEncoderRegistry(JSONEncoder{}, XMLEncoder{})
+	gc, w := newTestContext("text/html")
+	gc.Writer.Header().Set(string(HeaderVary), "Origin")
+
+	RespondWithRegistry(gc, registry, http.StatusOK, testItem{ID: "1"})
+
+	if w.Code != http.StatusNotAcceptable {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusNotAcceptable)
+	}
+	if !gc.IsAborted() {
+		t.Error("context not aborted")
+	}
+	if got := w.Header().Values(string(HeaderVary)); !slices.Equal(got, []string{"Origin, Accept"}) {
+		t.Errorf("Vary = %v, want [Origin, Accept]", got)
+	}
+	var failure FailureResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &failure); err != nil {
+		t.Fatalf("decode body %s: %v", w.Body.String(), err)
+	}
+	if failure.Success || failure.Error == nil || failure.Error.Code != OnNotAcceptable.Code {
+		t.Fatalf("body = %s, want error code %s", w.Body.String(), OnNotAcceptable.Code)
+	}
+	want := []ErrorDetail{{Field: "Accept", Message: "supported media types: application/json, application/xml"}}
+	if !slices.Equal(failure.Error.Details, want) {
+		t.Errorf("details = %+v, want %+v", failure.Error.Details, want)
+	}
+}
+
+func TestRespondWithRegistryFallsBackOnEncodeError(t *testing.T) {
+	registry := NewEncoderRegistry(testEncoder{mediaType: "text/plain", err: errors.New("broken")}, testEncoder{mediaType: "text/csv"})
+	gc, w := newTestContext("text/plain, text/csv;q=0.5")
+
+	RespondWithRegistry(gc, registry, http.StatusCreated, testItem{})
+
+	if w.Code != http.StatusCreated || w.Body.String() != "text/csv" {
+		t.Fatalf("response = %d %q, want %d %q", w.Code, w.Body.String(), http.StatusCreated, "text/csv")
+	}
+	if got := w.Header().Get(string(HeaderContentType)); got != "text/csv" {
+		t.Errorf("Content-Type = %q, want text/csv", got)
+	}
+}
+
+func TestAddVary(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing []string
+		want     []string
+	}{
+		{name: "empty", existing: nil, want: []string{"Accept"}},
+		{name: "merged", existing: []string{"Origin"}, want: []string{"Origin, Accept"}},
+		{name: "several lines", existing: []string{"Origin", "Accept-Encoding"}, want: []string{"Origin, Accept-Encoding, Accept"}},
+		{name: "already listed", existing: []string{"origin, accept"}, want: []string{"origin, accept"}},
+		{name: "star", existing: []string{"*"}, want: []string{"*"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			for _, value := range tt.existing {
+				header.Add(string(HeaderVary), value)
+			}
+			addVary(header, "Accept")
+			if got := header.Values(string(HeaderVary)); !slices.Equal(got, tt.want) {
+				t.Errorf("Vary = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
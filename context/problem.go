@@ -0,0 +1,152 @@
+package context
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+	ae "github.com/piyushkumar96/app-error"
+)
+
+// ErrorFormat selects how RespondError renders app errors.
+type ErrorFormat string
+
+const (
+	// ErrorFormatEnvelope renders the FailureResponse JSON envelope (default).
+	ErrorFormatEnvelope ErrorFormat = "envelope"
+	// ErrorFormatProblem renders RFC 9457 (formerly RFC 7807) application/problem+json documents.
+	ErrorFormatProblem ErrorFormat = "problem"
+)
+
+// ErrorFormatConfig is the process-wide error rendering configuration (see SetErrorFormatConfig).
+type ErrorFormatConfig struct {
+	// Default is used when neither the route (WithErrorFormat) nor the Accept header decides; empty means envelope.
+	Default ErrorFormat
+	// NegotiateAccept renders problem+json when the client lists application/problem+json in Accept with a
+	// quality at least that of application/json, and the envelope when it prefers application/json.
+	NegotiateAccept bool
+	// ProblemTypeBaseURI prefixes the lower-cased error code to build the problem type,
+	// e.g. "https://errors.example.com/" gives "https://errors.example.com/err_auth_001"; empty uses "about:blank".
+	ProblemTypeBaseURI string
+}
+
+// ProblemDetails is an RFC 9457 problem document; Code, RequestID and Errors are extension members.
+type ProblemDetails struct {
+	Type      string        `json:"type"`
+	Title     string        `json:"title"`
+	Status    int           `json:"status"`
+	Detail    string        `json:"detail,omitempty"`
+	Instance  string        `json:"instance,omitempty"`
+	Code      string        `json:"code,omitempty"`
+	RequestID string        `json:"request_id,omitempty"`
+	Errors    []ErrorDetail `json:"errors,omitempty"`
+}
+
+var errorFormatConfig atomic.Pointer[ErrorFormatConfig]
+
+// SetErrorFormatConfig replaces the process-wide error format configuration (nil restores the default).
+// Call it once at startup, before serving requests.
+func SetErrorFormatConfig(config *ErrorFormatConfig) {
+	errorFormatConfig.Store(config)
+}
+
+// GetErrorFormatConfig returns the process-wide error format configuration.
+func GetErrorFormatConfig() *ErrorFormatConfig {
+	if config := errorFormatConfig.Load(); config != nil {
+		return config
+	}
+	return &ErrorFormatConfig{Default: ErrorFormatEnvelope}
+}
+
+// WithErrorFormat returns a middleware that fixes the error format of the routes it is attached to,
+// taking precedence over Accept negotiation.
+func WithErrorFormat(format ErrorFormat) gin.HandlerFunc {
+	return func(gc *gin.Context) {
+		gc.Set(ErrorFormatKey, format)
+		gc.Next()
+	}
+}
+
+// NewProblemDetails builds the problem document of appErr. The type is derived from the error code, the title
+// from the status (a generic class title for unregistered codes such as 499), and instance is the request path.
+func NewProblemDetails(ctx context.Context, appErr *ae.AppError, statusCode int, instance string, details ...ErrorDetail) *ProblemDetails {
+	if len(details) == 0 {
+		details, _ = appErr.GetData().([]ErrorDetail)
+	}
+	return &ProblemDetails{
+		Type:      problemType(GetErrorFormatConfig().ProblemTypeBaseURI, appErr.GetErrCode()),
+		Title:     problemTitle(statusCode),
+		Status:    statusCode,
+		Detail:    appErr.GetMsg(),
+		Instance:  instance,
+		Code:      appErr.GetErrCode(),
+		RequestID: GetRequestID(ctx),
+		Errors:    details,
+	}
+}
+
+// RespondProblem writes appErr as application/problem+json and aborts with its HTTP status code
+// (500 when it has none).
+func RespondProblem(gc *gin.Context, appErr *ae.AppError, details ...ErrorDetail) {
+	statusCode := appErr.GetHTTPCode()
+	if statusCode == 0 {
+		statusCode = http.StatusInternalServerError
+	}
+	problem := NewProblemDetails(GetRequestContext(gc), appErr, statusCode, gc.Request.URL.Path, details...)
+	if problem.RequestID == "" {
+		problem.RequestID = gc.GetString(ReqIDKey)
+	}
+	gc.Header(string(HeaderContentType), string(ApplicationProblemJSON))
+	RespondJSON(gc, statusCode, problem)
+}
+
+// errorFormatOf resolves the error format of a request: route (WithErrorFormat), then Accept, then default.
+func errorFormatOf(gc *gin.Context) ErrorFormat {
+	if v, ok := gc.Get(ErrorFormatKey); ok {
+		if format, ok := v.(ErrorFormat); ok {
+			return format
+		}
+	}
+	config := GetErrorFormatConfig()
+	if config.NegotiateAccept {
+		if accept := gc.GetHeader("Accept"); accept != "" {
+			ranges := parseAccept(accept)
+			problemQ, explicit := acceptQuality(ranges, string(ApplicationProblemJSON))
+			jsonQ, _ := acceptQuality(ranges, string(ApplicationJSON))
+			switch {
+			case explicit && problemQ > 0 && problemQ >= jsonQ:
+				return ErrorFormatProblem
+			case jsonQ > 0 && jsonQ > problemQ:
+				return ErrorFormatEnvelope
+			}
+		}
+	}
+	if config.Default == "" {
+		return ErrorFormatEnvelope
+	}
+	return config.Default
+}
+
+// problemTitle returns the status text, falling back to the status class when the code is not registered.
+func problemTitle(statusCode int) string {
+	if title := http.StatusText(statusCode); title != "" {
+		return title
+	}
+	switch {
+	case statusCode >= http.StatusInternalServerError:
+		return problemTitleServerError
+	case statusCode >= http.StatusBadRequest:
+		return problemTitleClientError
+	default:
+		return problemTitleUnknown
+	}
+}
+
+func problemType(baseURI, code string) string {
+	if baseURI == "" || code == "" {
+		return problemTypeBlank
+	}
+	return baseURI + strings.ToLower(code)
+}
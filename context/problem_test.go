@@ -0,0 +1,106 @@
+package context
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	ae "github.com/piyushkumar96/app-error"
+)
+
+var errTestProblem = ae.GetCustomErr("ERR_TEST_1001", "test problem", false)
+
+// newTestContext returns a gin context for a GET request carrying accept (unset when empty).
+func newTestContext(accept string) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	gc, _ := gin.CreateTestContext(w)
+	gc.Request = httptest.NewRequest(http.MethodGet, "/items", nil)
+	if accept != "" {
+		gc.Request.Header.Set("Accept", accept)
+	}
+	return gc, w
+}
+
+func TestErrorFormatOfAccept(t *testing.T) {
+	tests := []struct {
+		name   string
+		accept string
+		// want is the format for Default envelope; wantProblemDefault for Default problem.
+		want               ErrorFormat
+		wantProblemDefault ErrorFormat
+	}{
+		{name: "no accept", accept: "", want: ErrorFormatEnvelope, wantProblemDefault: ErrorFormatProblem},
+		{name: "any", accept: "*/*", want: ErrorFormatEnvelope, wantProblemDefault: ErrorFormatProblem},
+		{name: "application wildcard", accept: "application/*", want: ErrorFormatEnvelope, wantProblemDefault: ErrorFormatProblem},
+		{name: "json", accept: "application/json", want: ErrorFormatEnvelope, wantProblemDefault: ErrorFormatEnvelope},
+		{name: "problem", accept: "application/problem+json", want: ErrorFormatProblem, wantProblemDefault: ErrorFormatProblem},
+		{name: "problem and json tie", accept: "application/json, application/problem+json", want: ErrorFormatProblem, wantProblemDefault: ErrorFormatProblem},
+		{name: "problem preferred", accept: "application/json;q=0.5, application/problem+json", want: ErrorFormatProblem, wantProblemDefault: ErrorFormatProblem},
+		{name: "json preferred", accept: "application/problem+json;q=0.5, application/json", want: ErrorFormatEnvelope, wantProblemDefault: ErrorFormatEnvelope},
+		{name: "problem over any", accept: "application/problem+json, */*;q=0.1", want: ErrorFormatProblem, wantProblemDefault: ErrorFormatProblem},
+		{name: "problem below any", accept: "application/problem+json;q=0.1, */*", want: ErrorFormatEnvelope, wantProblemDefault: ErrorFormatEnvelope},
+		{name: "problem refused", accept: "application/problem+json;q=0", want: ErrorFormatEnvelope, wantProblemDefault: ErrorFormatProblem},
+		{name: "problem refused with any", accept: "application/problem+json;q=0, */*", want: ErrorFormatEnvelope, wantProblemDefault: ErrorFormatEnvelope},
+		{name: "unrelated type", accept: "text/html", want: ErrorFormatEnvelope, wantProblemDefault: ErrorFormatProblem},
+	}
+	t.Cleanup(func() { SetErrorFormatConfig(nil) })
+	for _, defaultFormat := range []ErrorFormat{ErrorFormatEnvelope, ErrorFormatProblem} {
+		SetErrorFormatConfig(&ErrorFormatConfig{Default: defaultFormat, NegotiateAccept: true})
+		for _, tt := range tests {
+			t.Run(string(defaultFormat)+"/"+tt.name, func(t *testing.T) {
+				want := tt.want
+				if defaultFormat == ErrorFormatProblem {
+					want = tt.wantProblemDefault
+				}
+				gc, _ := newTestContext(tt.accept)
+				if got := errorFormatOf(gc); got != want {
+					t.Errorf("errorFormatOf(Accept %q) = %s, want %s", tt.accept, got, want)
+				}
+			})
+		}
+	}
+}
+
+func TestErrorFormatOfPrecedence(t *testing.T) {
+	t.Cleanup(func() { SetErrorFormatConfig(nil) })
+
+	SetErrorFormatConfig(&ErrorFormatConfig{Default: ErrorFormatEnvelope, NegotiateAccept: false})
+	gc, _ := newTestContext(string(ApplicationProblemJSON))
+	if got := errorFormatOf(gc); got != ErrorFormatEnvelope {
+		t.Errorf("without NegotiateAccept = %s, want %s", got, ErrorFormatEnvelope)
+	}
+
+	SetErrorFormatConfig(&ErrorFormatConfig{Default: ErrorFormatEnvelope, NegotiateAccept: true})
+	gc, _ = newTestContext(string(ApplicationJSON))
+	gc.Set(ErrorFormatKey, ErrorFormatProblem)
+	if got := errorFormatOf(gc); got != ErrorFormatProblem {
+		t.Errorf("route format = %s, want %s", got, ErrorFormatProblem)
+	}
+}
+
+func TestNewProblemDetailsTitle(t *testing.T) {
+	tests := []struct {
+		statusCode int
+		want       string
+	}{
+		{statusCode: http.StatusNotFound, want: "Not Found"},
+		{statusCode: http.StatusServiceUnavailable, want: "Service Unavailable"},
+		{statusCode: 499, want: problemTitleClientError},
+		{statusCode: 599, want: problemTitleServerError},
+		{statusCode: 299, want: problemTitleUnknown},
+	}
+	for _, tt := range tests {
+		appErr := ae.GetAppErr(context.Background(), errors.New("cause"), errTestProblem, tt.statusCode)
+		problem := NewProblemDetails(context.Background(), appErr, tt.statusCode, "/items")
+		if problem.Title != tt.want {
+			t.Errorf("title for %d = %q, want %q", tt.statusCode, problem.Title, tt.want)
+		}
+		if problem.Status != tt.statusCode || problem.Code != errTestProblem.Code || problem.Type != problemTypeBlank {
+			t.Errorf("problem for %d = %+v", tt.statusCode, problem)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package context
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RequestIDConfig configures how request IDs are read, validated, generated and echoed.
+type RequestIDConfig struct {
+	// InboundHeaders are checked in order; the first valid value is used.
+	InboundHeaders []string
+	// OutboundHeaders are set on every response with the request ID.
+	OutboundHeaders []string
+	// Generator creates an ID when no valid inbound one exists (e.g. NewUUIDv7RequestID).
+	Generator func() string
+	// MaxLength and Pattern validate inbound IDs so they are safe to log; invalid IDs are replaced.
+	MaxLength int
+	Pattern   *regexp.Regexp
+}
+
+var requestIDConfig atomic.Pointer[RequestIDConfig]
+
+// NewDefaultRequestIDConfig reads X-Request-ID then req-id, echoes both, and generates hex+nanos IDs.
+func NewDefaultRequestIDConfig() *RequestIDConfig {
+	return &RequestIDConfig{
+		InboundHeaders:  []string{string(HeaderRequestID), string(HeaderResponseReqID)},
+		OutboundHeaders: []string{string(HeaderRequestID), string(HeaderResponseReqID)},
+		Generator:       NewHexNanosRequestID,
+		MaxLength:       defaultRequestIDMaxLength,
+		Pattern:         requestIDPattern,
+	}
+}
+
+// SetRequestIDConfig replaces the process-wide request ID configuration (nil restores the default).
+// Call it once at startup, before serving requests.
+func SetRequestIDConfig(config *RequestIDConfig) {
+	requestIDConfig.Store(config)
+}
+
+// GetRequestIDConfig returns the process-wide request ID configuration.
+func GetRequestIDConfig() *RequestIDConfig {
+	if config := requestIDConfig.Load(); config != nil {
+		return config
+	}
+	return NewDefaultRequestIDConfig()
+}
+
+// ResolveRequestID returns the request ID of gc using the process-wide configuration. It is resolved once per
+// request: the first call reads/validates or generates it and echoes it on the response, later calls reuse it.
+func ResolveRequestID(gc *gin.Context) string {
+	return ResolveRequestIDWithConfig(gc, GetRequestIDConfig())
+}
+
+// ResolveRequestIDWithConfig is ResolveRequestID with an explicit configuration.
+func ResolveRequestIDWithConfig(gc *gin.Context, config *RequestIDConfig) string {
+	if reqID := gc.GetString(ReqIDKey); reqID != "" {
+		return reqID
+	}
+	reqID := ""
+	for _, header := range config.InboundHeaders {
+		if value := gc.GetHeader(header); config.IsValid(value) {
+			reqID = value
+			break
+		}
+	}
+	if reqID == "" {
+		reqID = config.NewRequestID()
+	}
+	for _, header := range config.OutboundHeaders {
+		gc.Header(header, reqID)
+	}
+	gc.Set(ReqIDKey, reqID)
+	return reqID
+}
+
+// NewRequestID generates an ID with Generator, falling back to NewHexNanosRequestID when it is nil.
+func (config *RequestIDConfig) NewRequestID() string {
+	if config.Generator == nil {
+		return NewHexNanosRequestID()
+	}
+	return config.Generator()
+}
+
+// IsValid reports whether an inbound request ID respects MaxLength and Pattern.
+func (config *RequestIDConfig) IsValid(reqID string) bool {
+	if reqID == "" {
+		return false
+	}
+	if config.MaxLength > 0 && len(reqID) > config.MaxLength {
+		return false
+	}
+	pattern := config.Pattern
+	if pattern == nil {
+		pattern = requestIDPattern
+	}
+	return pattern.MatchString(reqID)
+}
+
+// NewHexNanosRequestID is the legacy format: 16 random hex chars followed by the Unix time in nanoseconds.
+func NewHexNanosRequestID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b) + fmt.Sprintf("%d", time.Now().UnixNano())
+}
+
+// NewUUIDv4RequestID returns a random UUID.
+func NewUUIDv4RequestID() string {
+	return uuid.NewString()
+}
+
+// NewUUIDv7RequestID returns a time-ordered UUID (falls back to v4 if the clock source fails).
+func NewUUIDv7RequestID() string {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return uuid.NewString()
+	}
+	return id.String()
+}
+
+// NewULIDRequestID returns a ULID: 48 bit millisecond timestamp and 80 random bits, Crockford base32 encoded.
+func NewULIDRequestID() string {
+	var b [16]byte
+	binary.BigEndian.PutUint64(b[:8], uint64(time.Now().UnixMilli())<<16)
+	_, _ = rand.Read(b[6:])
+	// 128 bits -> 26 base32 chars; the first char carries only the top 3 bits
+	out := make([]byte, 26)
+	hi := binary.BigEndian.Uint64(b[:8])
+	lo := binary.BigEndian.Uint64(b[8:])
+	for i := 25; i >= 0; i-- {
+		out[i] = crockfordAlphabet[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out)
+}
@@ -0,0 +1,93 @@
+package context
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	ae "github.com/piyushkumar96/app-error"
+)
+
+// SuccessResponse is the JSON envelope of successful responses.
+type SuccessResponse struct {
+	Success   bool        `json:"success"`
+	Data      interface{} `json:"data,omitempty"`
+	Meta      *PageMeta   `json:"meta,omitempty"`
+	RequestID string      `json:"request_id,omitempty"`
+}
+
+// PageMeta describes the page of a paginated list; use either Page/PageSize or NextCursor.
+type PageMeta struct {
+	Page       int    `json:"page,omitempty"`
+	PageSize   int    `json:"page_size,omitempty"`
+	Total      int64  `json:"total,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// FailureResponse is the JSON envelope of error responses. Message repeats Error.Message for clients of the
+// legacy {success, message} body.
+type FailureResponse struct {
+	Success   bool       `json:"success"`
+	Message   string     `json:"message"`
+	Error     *ErrorBody `json:"error"`
+	RequestID string     `json:"request_id,omitempty"`
+}
+
+// ErrorBody carries the app-error code, its message and optional per-field details.
+type ErrorBody struct {
+	Code    string        `json:"code,omitempty"`
+	Message string        `json:"message"`
+	Details []ErrorDetail `json:"details,omitempty"`
+}
+
+// ErrorDetail describes one problem of a request, e.g. a failed validation of a field.
+type ErrorDetail struct {
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// NewSuccessResponse wraps data (and optional pagination meta) with the request ID of ctx.
+func NewSuccessResponse(ctx context.Context, data interface{}, meta *PageMeta) *SuccessResponse {
+	return &SuccessResponse{Success: true, Data: data, Meta: meta, RequestID: GetRequestID(ctx)}
+}
+
+// NewFailureResponse builds the error envelope of appErr with the request ID of ctx. Details default to the
+// app error data when it is a []ErrorDetail.
+func NewFailureResponse(ctx context.Context, appErr *ae.AppError, details ...ErrorDetail) *FailureResponse {
+	if len(details) == 0 {
+		details, _ = appErr.GetData().([]ErrorDetail)
+	}
+	return &FailureResponse{
+		Success:   false,
+		Message:   appErr.GetMsg(),
+		Error:     &ErrorBody{Code: appErr.GetErrCode(), Message: appErr.GetMsg(), Details: details},
+		RequestID: GetRequestID(ctx),
+	}
+}
+
+// RespondSuccess writes data in a SuccessResponse envelope and aborts with the given status code.
+func RespondSuccess(gc *gin.Context, statusCode int, data interface{}, meta *PageMeta) {
+	response := NewSuccessResponse(GetRequestContext(gc), data, meta)
+	if response.RequestID == "" {
+		response.RequestID = gc.GetString(ReqIDKey)
+	}
+	RespondJSON(gc, statusCode, response)
+}
+
+// RespondError writes appErr in a FailureResponse envelope, or as problem+json when the route or the Accept
+// header asks for it (see ErrorFormatConfig), and aborts with its HTTP status code (500 when it has none).
+func RespondError(gc *gin.Context, appErr *ae.AppError, details ...ErrorDetail) {
+	if errorFormatOf(gc) == ErrorFormatProblem {
+		RespondProblem(gc, appErr, details...)
+		return
+	}
+	statusCode := appErr.GetHTTPCode()
+	if statusCode == 0 {
+		statusCode = http.StatusInternalServerError
+	}
+	response := NewFailureResponse(GetRequestContext(gc), appErr, details...)
+	if response.RequestID == "" {
+		response.RequestID = gc.GetString(ReqIDKey)
+	}
+	RespondJSON(gc, statusCode, response)
+}
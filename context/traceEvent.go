@@ -0,0 +1,177 @@
+package context
+
+import (
+	"context"
+	"maps"
+	"sync"
+	"time"
+)
+
+// TraceSeverity classifies a TraceEvent.
+type TraceSeverity string
+
+const (
+	SeverityDebug TraceSeverity = "debug"
+	SeverityInfo  TraceSeverity = "info"
+	SeverityWarn  TraceSeverity = "warn"
+	SeverityError TraceSeverity = "error"
+)
+
+// EventAttrError is the attribute holding the error message of an error event.
+const EventAttrError = "error"
+
+// TraceEvent is one structured, timestamped step recorded in TraceMeta. Duration is zero for point events.
+type TraceEvent struct {
+	Name       string                 `json:"name"`
+	Time       time.Time              `json:"time"`
+	Duration   time.Duration          `json:"duration,omitempty"`
+	Severity   TraceSeverity          `json:"severity"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+}
+
+// EventSpan times a step started with StartEvent; End records it as a TraceEvent.
+type EventSpan struct {
+	mu         sync.Mutex
+	traceMeta  *TraceMeta
+	name       string
+	start      time.Time
+	severity   TraceSeverity
+	attributes map[string]interface{}
+	ended      bool
+}
+
+// AddEvent records an event. Error events are also appended to Error and all others to Trace,
+// which keeps the legacy string view filled.
+func (tm *TraceMeta) AddEvent(event TraceEvent) {
+	tm.addEvent(event, false)
+}
+
+// addEvent records an event; legacy entries (AddTrace) are kept even when the request is not recording.
+func (tm *TraceMeta) addEvent(event TraceEvent, legacy bool) {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	if event.Severity == "" {
+		event.Severity = SeverityInfo
+	}
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	if tm.skipVerbose && !legacy && (event.Severity == SeverityDebug || event.Severity == SeverityInfo) {
+		return
+	}
+	tm.events = append(tm.events, event)
+	if event.Severity == SeverityError {
+		errorMsg := event.Name
+		if cause, ok := event.Attributes[EventAttrError].(string); ok && cause != "" {
+			errorMsg = event.Name + UnderScore + cause
+		}
+		tm.Error = append(tm.Error, errorMsg)
+	} else {
+		tm.Trace = append(tm.Trace, event.Name)
+	}
+}
+
+// SetRecording turns full event capture on or off; when off only warn and error events, and the legacy AddTrace
+// entries, are kept.
+func (tm *TraceMeta) SetRecording(recording bool) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	tm.skipVerbose = !recording
+}
+
+// Recording reports whether debug and info events are captured.
+func (tm *TraceMeta) Recording() bool {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+	return !tm.skipVerbose
+}
+
+// Events returns a copy of the recorded events in order.
+func (tm *TraceMeta) Events() []TraceEvent {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+	return cloneEvents(tm.events)
+}
+
+// RecordEvent records a point event in the TraceMeta of ctx; it is a no-op when ctx has none.
+func RecordEvent(ctx context.Context, name string, severity TraceSeverity, attributes map[string]interface{}) {
+	if traceMeta := traceMetaFrom(ctx); traceMeta != nil {
+		traceMeta.AddEvent(TraceEvent{Name: name, Severity: severity, Attributes: maps.Clone(attributes)})
+	}
+}
+
+// StartEvent starts timing a step; call End (or EndWithError) on the returned span to record it with its duration.
+func StartEvent(ctx context.Context, name string, attributes map[string]interface{}) *EventSpan {
+	return &EventSpan{
+		traceMeta:  traceMetaFrom(ctx),
+		name:       name,
+		start:      time.Now(),
+		severity:   SeverityInfo,
+		attributes: maps.Clone(attributes),
+	}
+}
+
+// SetAttribute adds or replaces an attribute before the span ends.
+func (s *EventSpan) SetAttribute(key string, value interface{}) *EventSpan {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.attributes == nil {
+		s.attributes = make(map[string]interface{})
+	}
+	s.attributes[key] = value
+	return s
+}
+
+// SetSeverity changes the severity the span is recorded with.
+func (s *EventSpan) SetSeverity(severity TraceSeverity) *EventSpan {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.severity = severity
+	return s
+}
+
+// End records the span with its elapsed time and returns the event; only the first call records.
+func (s *EventSpan) End() TraceEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	event := TraceEvent{
+		Name:       s.name,
+		Time:       s.start,
+		Duration:   time.Since(s.start),
+		Severity:   s.severity,
+		Attributes: maps.Clone(s.attributes),
+	}
+	if !s.ended && s.traceMeta != nil {
+		s.traceMeta.AddEvent(event)
+	}
+	s.ended = true
+	return event
+}
+
+// EndWithError records the span with error severity and the error message as the EventAttrError attribute.
+func (s *EventSpan) EndWithError(err error) TraceEvent {
+	if err != nil {
+		s.SetSeverity(SeverityError).SetAttribute(EventAttrError, err.Error())
+	}
+	return s.End()
+}
+
+func traceMetaFrom(ctx context.Context) *TraceMeta {
+	if ctx == nil {
+		return nil
+	}
+	traceMeta, _ := ctx.Value(TraceMetaKey).(*TraceMeta)
+	return traceMeta
+}
+
+func cloneEvents(events []TraceEvent) []TraceEvent {
+	if events == nil {
+		return nil
+	}
+	cloned := make([]TraceEvent, len(events))
+	for i, event := range events {
+		event.Attributes = maps.Clone(event.Attributes)
+		cloned[i] = event
+	}
+	return cloned
+}
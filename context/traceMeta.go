@@ -0,0 +1,126 @@
+package context
+
+import (
+	"context"
+	"maps"
+	"slices"
+)
+
+// TraceMetaSnapshot is a point-in-time copy of a TraceMeta, safe to read without locking.
+type TraceMetaSnapshot struct {
+	Events             []TraceEvent
+	Trace              []string
+	Error              []string
+	IdentifierMappings map[string]interface{}
+}
+
+// AddTrace records an info event named entry, whether or not the request is recording (see SetRecording).
+func (tm *TraceMeta) AddTrace(entry string) {
+	tm.addEvent(TraceEvent{Name: entry, Severity: SeverityInfo}, true)
+}
+
+// AddError records an error event named errorMsg.
+func (tm *TraceMeta) AddError(errorMsg string) {
+	tm.AddEvent(TraceEvent{Name: errorMsg, Severity: SeverityError})
+}
+
+// SetIdentifier stores an identifier mapping, creating the map on first use.
+func (tm *TraceMeta) SetIdentifier(key string, value interface{}) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	if tm.IdentifierMappings == nil {
+		tm.IdentifierMappings = make(map[string]interface{})
+	}
+	tm.IdentifierMappings[key] = value
+}
+
+// GetIdentifier returns an identifier mapping.
+func (tm *TraceMeta) GetIdentifier(key string) (interface{}, bool) {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+	value, ok := tm.IdentifierMappings[key]
+	return value, ok
+}
+
+// Identifiers returns a copy of the identifier mappings.
+func (tm *TraceMeta) Identifiers() map[string]interface{} {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+	return maps.Clone(tm.IdentifierMappings)
+}
+
+// Traces returns a copy of the trace entries.
+func (tm *TraceMeta) Traces() []string {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+	return slices.Clone(tm.Trace)
+}
+
+// Errors returns a copy of the error entries.
+func (tm *TraceMeta) Errors() []string {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+	return slices.Clone(tm.Error)
+}
+
+// Snapshot returns a copy of everything recorded so far.
+func (tm *TraceMeta) Snapshot() TraceMetaSnapshot {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+	return TraceMetaSnapshot{
+		Events:             cloneEvents(tm.events),
+		Trace:              slices.Clone(tm.Trace),
+		Error:              slices.Clone(tm.Error),
+		IdentifierMappings: maps.Clone(tm.IdentifierMappings),
+	}
+}
+
+// Copy returns an independent TraceMeta holding a copy of tm's entries and recording state.
+func (tm *TraceMeta) Copy() *TraceMeta {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+	return &TraceMeta{
+		events:             cloneEvents(tm.events),
+		skipVerbose:        tm.skipVerbose,
+		Trace:              slices.Clone(tm.Trace),
+		Error:              slices.Clone(tm.Error),
+		IdentifierMappings: maps.Clone(tm.IdentifierMappings),
+	}
+}
+
+// Merge appends child's entries after tm's, keeping the child's order, and copies its identifier mappings
+// (child values win). The whole child is merged atomically, so concurrent merges never interleave.
+func (tm *TraceMeta) Merge(child *TraceMeta) {
+	if child == nil || child == tm {
+		return
+	}
+	snapshot := child.Snapshot()
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	tm.events = append(tm.events, snapshot.Events...)
+	tm.Trace = append(tm.Trace, snapshot.Trace...)
+	tm.Error = append(tm.Error, snapshot.Error...)
+	if len(snapshot.IdentifierMappings) > 0 {
+		if tm.IdentifierMappings == nil {
+			tm.IdentifierMappings = make(map[string]interface{}, len(snapshot.IdentifierMappings))
+		}
+		maps.Copy(tm.IdentifierMappings, snapshot.IdentifierMappings)
+	}
+}
+
+// WithChildTraceMeta returns a context whose TraceMeta is a new, empty child for work done in another goroutine.
+// The child inherits the parent's recording state. Merge it back with MergeChildTraceMeta once the goroutine is done.
+func WithChildTraceMeta(ctx context.Context) (context.Context, *TraceMeta) {
+	key, child := InitTraceMeta()
+	if parent := traceMetaFrom(ctx); parent != nil {
+		child.SetRecording(parent.Recording())
+	}
+	return context.WithValue(ctx, key, child), child
+}
+
+// MergeChildTraceMeta merges child into the TraceMeta of ctx (see TraceMeta.Merge).
+func MergeChildTraceMeta(ctx context.Context, child *TraceMeta) {
+	if parent, ok := ctx.Value(TraceMetaKey).(*TraceMeta); ok {
+		parent.Merge(child)
+	}
+}
@@ -0,0 +1,171 @@
+package context
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+const (
+	testWriters       = 8
+	testEntriesPerRun = 200
+)
+
+func TestTraceMetaConcurrentWritesAndReads(t *testing.T) {
+	_, tm := InitTraceMeta()
+
+	var wg sync.WaitGroup
+	for w := 0; w < testWriters; w++ {
+		wg.Add(3)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < testEntriesPerRun; i++ {
+				if i%2 == 0 {
+					tm.AddError(fmt.Sprintf("w%d-e%d", w, i))
+				} else {
+					tm.AddTrace(fmt.Sprintf("w%d-e%d", w, i))
+				}
+			}
+		}(w)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < testEntriesPerRun; i++ {
+				tm.SetIdentifier(fmt.Sprintf("w%d", w), i)
+			}
+		}(w)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < testEntriesPerRun; i++ {
+				_ = tm.Snapshot()
+				_ = tm.Traces()
+				_ = tm.Errors()
+				_ = tm.Identifiers()
+				_, _ = tm.GetIdentifier("w0")
+				_ = tm.Copy()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got, want := len(tm.Traces())+len(tm.Errors()), testWriters*testEntriesPerRun; got != want {
+		t.Fatalf("traces+errors = %d, want %d", got, want)
+	}
+	identifiers := tm.Identifiers()
+	if len(identifiers) != testWriters {
+		t.Fatalf("identifiers = %d, want %d", len(identifiers), testWriters)
+	}
+	for w := 0; w < testWriters; w++ {
+		if got := identifiers[fmt.Sprintf("w%d", w)]; got != testEntriesPerRun-1 {
+			t.Errorf("identifier w%d = %v, want %d", w, got, testEntriesPerRun-1)
+		}
+	}
+}
+
+func TestTraceMetaConcurrentChildMerges(t *testing.T) {
+	key, parent := InitTraceMeta()
+	ctx := context.WithValue(context.Background(), key, parent)
+
+	var wg sync.WaitGroup
+	for w := 0; w < testWriters; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			childCtx, child := WithChildTraceMeta(ctx)
+			for i := 0; i < testEntriesPerRun; i++ {
+				AddTrace(childCtx, fmt.Sprintf("w%d", w), fmt.Sprintf("e%d", i))
+			}
+			child.SetIdentifier(fmt.Sprintf("w%d", w), w)
+			MergeChildTraceMeta(ctx, child)
+		}(w)
+	}
+	wg.Wait()
+
+	traces := parent.Traces()
+	if len(traces) != testWriters*testEntriesPerRun {
+		t.Fatalf("traces = %d, want %d", len(traces), testWriters*testEntriesPerRun)
+	}
+	// each child is merged atomically, so its entries stay contiguous and in order
+	for start := 0; start < len(traces); start += testEntriesPerRun {
+		var w int
+		if _, err := fmt.Sscanf(traces[start], "w%d_e0", &w); err != nil {
+			t.Fatalf("trace %d = %q, want the first entry of a child", start, traces[start])
+		}
+		for i := 0; i < testEntriesPerRun; i++ {
+			if want := fmt.Sprintf("w%d_e%d", w, i); traces[start+i] != want {
+				t.Fatalf("trace %d = %q, want %q", start+i, traces[start+i], want)
+			}
+		}
+	}
+	if got := len(parent.Identifiers()); got != testWriters {
+		t.Fatalf("identifiers = %d, want %d", got, testWriters)
+	}
+}
+
+func TestTraceMetaCopyIsIndependent(t *testing.T) {
+	_, tm := InitTraceMeta()
+	tm.AddTrace("step")
+	tm.SetIdentifier("order", "o-1")
+
+	copied := tm.Copy()
+	copied.AddTrace("copied-step")
+	copied.SetIdentifier("order", "o-2")
+
+	if got := tm.Traces(); len(got) != 1 || got[0] != "step" {
+		t.Errorf("original traces = %v, want [step]", got)
+	}
+	if got, _ := tm.GetIdentifier("order"); got != "o-1" {
+		t.Errorf("original identifier = %v, want o-1", got)
+	}
+}
+
+func TestWithChildTraceMetaInheritsRecording(t *testing.T) {
+	tests := []struct {
+		name      string
+		recording bool
+		wantInfo  int
+	}{
+		{name: "sampled parent", recording: true, wantInfo: 1},
+		{name: "unsampled parent", recording: false, wantInfo: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, parent := InitTraceMeta()
+			parent.SetRecording(tt.recording)
+			ctx := context.WithValue(context.Background(), key, parent)
+
+			childCtx, child := WithChildTraceMeta(ctx)
+			if child.Recording() != tt.recording {
+				t.Fatalf("child recording = %v, want %v", child.Recording(), tt.recording)
+			}
+			RecordEvent(childCtx, "step", SeverityInfo, nil)
+			RecordEvent(childCtx, "failed", SeverityError, nil)
+			if got := len(child.Traces()); got != tt.wantInfo {
+				t.Errorf("child info events = %d, want %d", got, tt.wantInfo)
+			}
+			if got := len(child.Errors()); got != 1 {
+				t.Errorf("child error events = %d, want 1", got)
+			}
+			if child.Copy().Recording() != tt.recording {
+				t.Errorf("copy recording = %v, want %v", !tt.recording, tt.recording)
+			}
+		})
+	}
+}
+
+func TestAddTraceIgnoresRecording(t *testing.T) {
+	key, tm := InitTraceMeta()
+	tm.SetRecording(false)
+	ctx := context.WithValue(context.Background(), key, tm)
+
+	AddTrace(ctx, "legacy", "step")
+	RecordEvent(ctx, "verbose", SeverityInfo, nil)
+	RecordEvent(ctx, "slow", SeverityWarn, nil)
+
+	if got := tm.Traces(); len(got) != 2 || got[0] != "legacy_step" || got[1] != "slow" {
+		t.Errorf("traces = %v, want [legacy_step slow]", got)
+	}
+	if got := len(tm.Events()); got != 2 {
+		t.Errorf("events = %d, want 2", got)
+	}
+}
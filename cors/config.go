@@ -0,0 +1,155 @@
+package cors
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigError describes one invalid field of a CORS configuration.
+type ConfigError struct {
+	Field  string
+	Value  string
+	Reason string
+}
+
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("%s: invalid %s %q: %s", ErrInvalidConfig.Code, e.Field, e.Value, e.Reason)
+}
+
+// LoadCORSHeadersFromFile reads a YAML (.yaml/.yml) or JSON (.json) file, rejects unknown keys and validates the result.
+func LoadCORSHeadersFromFile(path string) (*CORSHeaders, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s: %w", ErrLoadConfig.Code, ErrLoadConfig.Message, err)
+	}
+	corsHeaders := &CORSHeaders{}
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		err = decoder.Decode(corsHeaders)
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(corsHeaders)
+	default:
+		err = fmt.Errorf("unsupported file extension %q (want .yaml, .yml or .json)", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s %s: %w", ErrLoadConfig.Code, ErrLoadConfig.Message, path, err)
+	}
+	if err = corsHeaders.Validate(); err != nil {
+		return nil, err
+	}
+	return corsHeaders, nil
+}
+
+// LoadCORSHeadersFromEnv builds CORSHeaders from prefixed environment variables, e.g. with prefix "CORS_":
+// CORS_ALLOW_ORIGIN, CORS_MAX_AGE, CORS_ALLOW_METHODS, CORS_ALLOW_HEADERS, CORS_ALLOW_CREDENTIALS, CORS_MODE.
+// Unknown variables carrying the prefix are rejected so typos do not go unnoticed.
+func LoadCORSHeadersFromEnv(prefix string) (*CORSHeaders, error) {
+	corsHeaders := &CORSHeaders{}
+	fields := map[string]*string{
+		prefix + "ALLOW_ORIGIN":      &corsHeaders.AccessControlAllowOrigin,
+		prefix + "MAX_AGE":           &corsHeaders.AccessControlMaxAge,
+		prefix + "ALLOW_METHODS":     &corsHeaders.AccessControlAllowMethods,
+		prefix + "ALLOW_HEADERS":     &corsHeaders.AccessControlAllowHeaders,
+		prefix + "ALLOW_CREDENTIALS": &corsHeaders.AccessControlAllowCredentials,
+	}
+	var errs []error
+	for _, kv := range os.Environ() {
+		key, value, _ := strings.Cut(kv, "=")
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		if key == prefix+"MODE" {
+			corsHeaders.Mode = CORSMode(value)
+			continue
+		}
+		field, ok := fields[key]
+		if !ok {
+			errs = append(errs, &ConfigError{Field: "environment variable", Value: key, Reason: "unknown key"})
+			continue
+		}
+		*field = value
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	if err := corsHeaders.Validate(); err != nil {
+		return nil, err
+	}
+	return corsHeaders, nil
+}
+
+// Validate checks the configuration strictly and returns every problem found (joined), or nil.
+func (corsHeaders *CORSHeaders) Validate() error {
+	var errs []error
+	addErr := func(field, value, reason string) {
+		errs = append(errs, &ConfigError{Field: field, Value: value, Reason: reason})
+	}
+
+	originRegex, err := regexp.Compile(corsHeaders.AccessControlAllowOrigin)
+	switch {
+	case corsHeaders.AccessControlAllowOrigin == "":
+		addErr("allow_origin", "", "must not be empty")
+	case err != nil:
+		addErr("allow_origin", corsHeaders.AccessControlAllowOrigin, err.Error())
+	}
+
+	switch corsHeaders.AccessControlAllowCredentials {
+	case "", "false":
+	case "true":
+		if originRegex != nil && originRegex.MatchString(wildcardProbeOrigin) {
+			addErr("allow_credentials", "true", "credentials cannot be combined with a wildcard allow_origin")
+		}
+	default:
+		addErr("allow_credentials", corsHeaders.AccessControlAllowCredentials, `must be "true" or "false"`)
+	}
+
+	if corsHeaders.AccessControlMaxAge != "" {
+		if maxAge, err := strconv.Atoi(corsHeaders.AccessControlMaxAge); err != nil || maxAge < 0 {
+			addErr("max_age", corsHeaders.AccessControlMaxAge, "must be a non-negative number of seconds")
+		}
+	}
+
+	for _, method := range splitList(corsHeaders.AccessControlAllowMethods) {
+		if _, ok := knownMethods[strings.ToUpper(method)]; !ok {
+			addErr("allow_methods", method, "unknown HTTP method")
+		}
+	}
+
+	for _, header := range splitList(corsHeaders.AccessControlAllowHeaders) {
+		if !headerNameRegex.MatchString(header) {
+			addErr("allow_headers", header, "not a valid header name")
+		}
+	}
+
+	switch corsHeaders.Mode {
+	case "", ModeEnforce, ModeReportOnly:
+	default:
+		addErr("mode", string(corsHeaders.Mode), fmt.Sprintf("must be %q or %q", ModeEnforce, ModeReportOnly))
+	}
+
+	return errors.Join(errs...)
+}
+
+// splitList splits a comma separated header value, dropping empty items.
+func splitList(value string) []string {
+	items := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
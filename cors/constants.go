@@ -0,0 +1,27 @@
+package cors
+
+import "regexp"
+
+// CORSMode decides what the CORS middleware does when a request violates the policy.
+type CORSMode string
+
+const (
+	// ModeEnforce aborts requests whose origin is not allowed (default).
+	ModeEnforce CORSMode = "enforce"
+	// ModeReportOnly evaluates the policy and reports violations but never aborts.
+	ModeReportOnly CORSMode = "report-only"
+)
+
+// defaultMaxAge is the Access-Control-Max-Age sent when the policy sets none.
+const defaultMaxAge = "86400"
+
+// wildcardProbeOrigin is an origin no real policy lists; a regex matching it is treated as a wildcard.
+const wildcardProbeOrigin = "https://cors-wildcard-probe.invalid"
+
+// knownMethods are the methods accepted in AccessControlAllowMethods (matched case-insensitively).
+var knownMethods = map[string]struct{}{
+	"GET": {}, "HEAD": {}, "POST": {}, "PUT": {}, "PATCH": {}, "DELETE": {}, "OPTIONS": {}, "CONNECT": {}, "TRACE": {},
+}
+
+// headerNameRegex matches an RFC 9110 field name (token).
+var headerNameRegex = regexp.MustCompile("^[!#$%&'*+.^_`|~0-9A-Za-z-]+$")
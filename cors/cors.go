@@ -4,43 +4,143 @@ package cors
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	im "github.com/piyushkumar96/app-monitoring/interfaces"
 	l "github.com/piyushkumar96/generic-logger"
 )
 
+// ViolationMetricsInterface records CORS violations on a metric labeled by error code, origin and route.
+type ViolationMetricsInterface interface {
+	LogViolation(code, origin, route string)
+}
+
 type CORSHeaders struct {
-	ContentType                   string
-	AccessControlAllowOrigin      string
-	AccessControlMaxAge           string
-	AccessControlAllowMethods     string
-	AccessControlAllowHeaders     string
-	AccessControlAllowCredentials string
+	// Deprecated: CORS no longer sets Content-Type; use responsedefaults.ResponseDefaults instead.
+	ContentType                   string `json:"content_type,omitempty" yaml:"content_type,omitempty"`
+	AccessControlAllowOrigin      string `json:"allow_origin" yaml:"allow_origin"`
+	AccessControlMaxAge           string `json:"max_age,omitempty" yaml:"max_age,omitempty"`
+	AccessControlAllowMethods     string `json:"allow_methods,omitempty" yaml:"allow_methods,omitempty"`
+	AccessControlAllowHeaders     string `json:"allow_headers,omitempty" yaml:"allow_headers,omitempty"`
+	AccessControlAllowCredentials string `json:"allow_credentials,omitempty" yaml:"allow_credentials,omitempty"`
+	// Mode is ModeEnforce (default when empty) or ModeReportOnly.
+	Mode CORSMode `json:"mode,omitempty" yaml:"mode,omitempty"`
+	// AppMetrics, when set, counts every violation under ErrOriginNotAllowed.Code.
+	AppMetrics im.AppMetricsInterface `json:"-" yaml:"-"`
+	// ViolationMetrics, when set, records every violation with its origin and route as metric labels.
+	ViolationMetrics ViolationMetricsInterface `json:"-" yaml:"-"`
 }
 
-// CORS will handle the CORS middleware
+// CORS will handle the CORS middleware. It stops the service when corsHeaders fail Validate, so a policy
+// combining credentials with a wildcard origin is never served.
 func CORS(corsHeaders *CORSHeaders) gin.HandlerFunc {
+	mustValidate(corsHeaders)
 	return func(c *gin.Context) {
+		handleCORS(c, corsHeaders)
+	}
+}
+
+// CORSWithPolicy is CORS backed by a CORSPolicy, so the headers can be hot-swapped (see WatchCORSFile).
+// The headers loaded at setup are validated like in CORS; reloads are validated by the loaders.
+// Requests pass through without CORS headers while the policy holds no headers.
+func CORSWithPolicy(policy *CORSPolicy) gin.HandlerFunc {
+	if corsHeaders := policy.Load(); corsHeaders != nil {
+		mustValidate(corsHeaders)
+	}
+	return func(c *gin.Context) {
+		corsHeaders := policy.Load()
+		if corsHeaders == nil {
+			if l.Logger != nil {
+				l.Logger.Error(ErrPolicyNotLoaded.Message, "code", ErrPolicyNotLoaded.Code)
+			}
+			c.Next()
+			return
+		}
+		handleCORS(c, corsHeaders)
+	}
+}
+
+func handleCORS(c *gin.Context, corsHeaders *CORSHeaders) {
+	if l.Logger != nil {
 		l.Logger.Debug("Middleware > Cors() logic starts")
-		matched, err := MatchStringWithRegex(corsHeaders.AccessControlAllowOrigin, c.GetHeader("Origin"))
-		if err != nil {
+	}
+	// The response depends on Origin even when it is absent, so caches must key on it.
+	c.Writer.Header().Add("Vary", "Origin")
+	origin := c.GetHeader("Origin")
+	// Requests without Origin (same-origin or server-to-server) are not cross-origin, so the policy is not checked.
+	matched := origin == ""
+	if !matched {
+		var err error
+		matched, err = MatchStringWithRegex(corsHeaders.AccessControlAllowOrigin, origin)
+		if err != nil && l.Logger != nil {
 			l.Logger.Fatal("invalid allow origin regex pattern", "err", err.Error())
 		}
-		if !matched {
+	}
+	if !matched {
+		reportViolation(c, corsHeaders, origin)
+		if corsHeaders.Mode != ModeReportOnly {
 			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+	}
+	if matched && origin != "" {
+		// An allowed origin is echoed so credentials can be sent; Validate rejects credentials with a wildcard.
+		c.Header("Access-Control-Allow-Origin", origin)
+		if corsHeaders.AccessControlAllowCredentials == "true" {
+			c.Header("Access-Control-Allow-Credentials", "true")
 		}
-		c.Header("Content-Type", "application/json")
+	} else {
 		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Max-Age", "86400")
-		c.Header("Access-Control-Allow-Methods", corsHeaders.AccessControlAllowMethods)
-		c.Header("Access-Control-Allow-Headers", corsHeaders.AccessControlAllowHeaders)
-		c.Header("Access-Control-Allow-Credentials", "true")
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(http.StatusNoContent)
-			return
-		} else {
+	}
+	maxAge := corsHeaders.AccessControlMaxAge
+	if maxAge == "" {
+		maxAge = defaultMaxAge
+	}
+	c.Header("Access-Control-Max-Age", maxAge)
+	c.Header("Access-Control-Allow-Methods", corsHeaders.AccessControlAllowMethods)
+	c.Header("Access-Control-Allow-Headers", corsHeaders.AccessControlAllowHeaders)
+	if strings.EqualFold(c.Request.Method, http.MethodOptions) {
+		c.AbortWithStatus(http.StatusNoContent)
+		return
+	} else {
+		if l.Logger != nil {
 			l.Logger.Debug("Middleware > Cors() logic ends")
-			c.Next()
 		}
+		c.Next()
+	}
+}
+
+// mustValidate stops the service when corsHeaders are invalid.
+func mustValidate(corsHeaders *CORSHeaders) {
+	err := corsHeaders.Validate()
+	if err == nil {
+		return
+	}
+	if l.Logger != nil {
+		l.Logger.Fatal(ErrInvalidConfig.Message, "code", ErrInvalidConfig.Code, "err", err.Error())
+	}
+	panic(err)
+}
+
+// reportViolation logs and counts a request whose origin is not allowed by the policy.
+func reportViolation(c *gin.Context, corsHeaders *CORSHeaders, origin string) {
+	route := routeOf(c)
+	if l.Logger != nil {
+		l.Logger.Warn(ErrOriginNotAllowed.Message, "code", ErrOriginNotAllowed.Code, "mode", modeOf(corsHeaders),
+			"origin", origin, "route", route, "method", c.Request.Method)
+	}
+	if corsHeaders.AppMetrics != nil {
+		corsHeaders.AppMetrics.LogMetrics([]string{ErrOriginNotAllowed.Code})
+	}
+	if corsHeaders.ViolationMetrics != nil {
+		corsHeaders.ViolationMetrics.LogViolation(ErrOriginNotAllowed.Code, origin, route)
+	}
+}
+
+func modeOf(corsHeaders *CORSHeaders) CORSMode {
+	if corsHeaders.Mode == "" {
+		return ModeEnforce
 	}
+	return corsHeaders.Mode
 }
@@ -0,0 +1,148 @@
+package cors
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	im "github.com/piyushkumar96/app-monitoring/interfaces"
+)
+
+const testAllowOrigin = `^https://([a-z0-9-]+\.)*example\.com$`
+
+// violation is one call recorded by violationRecorder.
+type violation struct {
+	code, origin, route string
+}
+
+type violationRecorder struct {
+	violations []violation
+}
+
+func (r *violationRecorder) LogViolation(code, origin, route string) {
+	r.violations = append(r.violations, violation{code: code, origin: origin, route: route})
+}
+
+// newTestRouter serves /items/:id behind CORS. The logger is left unset on purpose: the middleware must not need one.
+func newTestRouter(corsHeaders *CORSHeaders) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(CORS(corsHeaders))
+	r.GET("/items/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return r
+}
+
+func serve(r *gin.Engine, method, origin string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(method, "/items/1", nil)
+	if origin != "" {
+		req.Header.Set("Origin", origin)
+	}
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestCORSViolations(t *testing.T) {
+	tests := []struct {
+		name           string
+		mode           CORSMode
+		origin         string
+		wantStatus     int
+		wantAllow      string
+		wantViolations []violation
+	}{
+		{name: "enforce allowed origin", mode: ModeEnforce, origin: "https://app.example.com",
+			wantStatus: http.StatusOK, wantAllow: "https://app.example.com"},
+		{name: "enforce violation", mode: ModeEnforce, origin: "https://evil.test",
+			wantStatus:     http.StatusUnauthorized,
+			wantViolations: []violation{{code: ErrOriginNotAllowed.Code, origin: "https://evil.test", route: "/items/:id"}}},
+		{name: "enforce without origin", mode: ModeEnforce, origin: "",
+			wantStatus: http.StatusOK, wantAllow: "*"},
+		{name: "report-only allowed origin", mode: ModeReportOnly, origin: "https://app.example.com",
+			wantStatus: http.StatusOK, wantAllow: "https://app.example.com"},
+		{name: "report-only violation", mode: ModeReportOnly, origin: "https://evil.test",
+			wantStatus: http.StatusOK, wantAllow: "*",
+			wantViolations: []violation{{code: ErrOriginNotAllowed.Code, origin: "https://evil.test", route: "/items/:id"}}},
+		{name: "report-only without origin", mode: ModeReportOnly, origin: "",
+			wantStatus: http.StatusOK, wantAllow: "*"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			appMetrics := im.NewMockAppMetrics()
+			recorder := &violationRecorder{}
+			r := newTestRouter(&CORSHeaders{
+				AccessControlAllowOrigin:  testAllowOrigin,
+				AccessControlAllowMethods: "GET, POST",
+				Mode:                      tt.mode,
+				AppMetrics:                appMetrics,
+				ViolationMetrics:          recorder,
+			})
+
+			w := serve(r, http.MethodGet, tt.origin)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Origin"); got != tt.wantAllow {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.wantAllow)
+			}
+			if !slices.Equal(recorder.violations, tt.wantViolations) {
+				t.Errorf("violations = %v, want %v", recorder.violations, tt.wantViolations)
+			}
+			wantCodes := []string(nil)
+			if len(tt.wantViolations) > 0 {
+				wantCodes = []string{ErrOriginNotAllowed.Code}
+			}
+			if !slices.Equal(appMetrics.LogMetricsErrCodes, wantCodes) {
+				t.Errorf("metrics codes = %v, want %v", appMetrics.LogMetricsErrCodes, wantCodes)
+			}
+		})
+	}
+}
+
+func TestCORSResponseHeaders(t *testing.T) {
+	r := newTestRouter(&CORSHeaders{
+		AccessControlAllowOrigin:      testAllowOrigin,
+		AccessControlAllowMethods:     "GET, POST",
+		AccessControlAllowHeaders:     "Authorization",
+		AccessControlAllowCredentials: "true",
+	})
+
+	w := serve(r, http.MethodGet, "https://app.example.com")
+	want := map[string]string{
+		"Access-Control-Allow-Origin":      "https://app.example.com",
+		"Access-Control-Allow-Credentials": "true",
+		"Access-Control-Max-Age":           defaultMaxAge,
+		"Access-Control-Allow-Methods":     "GET, POST",
+		"Access-Control-Allow-Headers":     "Authorization",
+		"Vary":                             "Origin",
+	}
+	for header, value := range want {
+		if got := w.Header().Get(header); got != value {
+			t.Errorf("%s = %q, want %q", header, got, value)
+		}
+	}
+
+	w = serve(r, http.MethodGet, "")
+	if got := w.Header().Get("Vary"); got != "Origin" {
+		t.Errorf("Vary without Origin = %q, want Origin", got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "" {
+		t.Errorf("Access-Control-Allow-Credentials without Origin = %q, want none", got)
+	}
+
+	w = serve(r, http.MethodOptions, "https://app.example.com")
+	if w.Code != http.StatusNoContent {
+		t.Errorf("preflight status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+}
+
+func TestCORSRefusesCredentialedWildcard(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("CORS accepted a wildcard origin with credentials")
+		}
+	}()
+	CORS(&CORSHeaders{AccessControlAllowOrigin: ".*", AccessControlAllowCredentials: "true"})
+}
@@ -0,0 +1,31 @@
+package cors
+
+import (
+	ae "github.com/piyushkumar96/app-error"
+)
+
+var (
+	// ErrOriginNotAllowed is recorded when the request origin does not match the allow origin regex.
+	ErrOriginNotAllowed = ae.GetCustomErr(
+		"ERR_CORS_1001",
+		"origin is not allowed by cors policy",
+		false)
+
+	// ErrInvalidConfig prefixes every CORS configuration validation error.
+	ErrInvalidConfig = ae.GetCustomErr(
+		"ERR_CORS_1002",
+		"invalid cors configuration",
+		false)
+
+	// ErrLoadConfig is returned when a CORS configuration file cannot be read or decoded.
+	ErrLoadConfig = ae.GetCustomErr(
+		"ERR_CORS_1003",
+		"failed to load cors configuration",
+		false)
+
+	// ErrPolicyNotLoaded is logged when CORSWithPolicy serves a request before any headers were stored.
+	ErrPolicyNotLoaded = ae.GetCustomErr(
+		"ERR_CORS_1004",
+		"cors policy has no headers loaded",
+		false)
+)
@@ -15,14 +15,16 @@ func main() {
 	gin.SetMode(gin.ReleaseMode)
 	r := gin.New()
 
-	// Use regex for AccessControlAllowOrigin; ".*" allows any origin.
+	// Use regex for AccessControlAllowOrigin; a matched origin is echoed back. A wildcard such as ".*" cannot be
+	// combined with AccessControlAllowCredentials "true" (CORS refuses it). Requests without Origin are not checked.
 	headers := &cors.CORSHeaders{
-		ContentType:                   "application/json",
-		AccessControlAllowOrigin:      ".*",
+		AccessControlAllowOrigin:      `^https?://(localhost(:[0-9]+)?|([a-z0-9-]+\.)*example\.com)$`,
 		AccessControlMaxAge:           "86400",
-		AccessControlAllowMethods:     "POST, GET, PUT, DELETE, UPDATE",
+		AccessControlAllowMethods:     "POST, GET, PUT, PATCH, DELETE",
 		AccessControlAllowHeaders:     "Content-Type, Authorization, X-Request-ID",
 		AccessControlAllowCredentials: "true",
+		// Switch to cors.ModeReportOnly to log and count would-be violations without blocking them.
+		Mode: cors.ModeEnforce,
 	}
 	r.Use(cors.CORS(headers))
 
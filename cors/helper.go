@@ -1,6 +1,10 @@
 package cors
 
-import "regexp"
+import (
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+)
 
 func MatchStringWithRegex(pattern, inputStr string) (bool, error) {
 	// Compile the regex pattern
@@ -10,3 +14,11 @@ func MatchStringWithRegex(pattern, inputStr string) (bool, error) {
 	}
 	return regex.MatchString(inputStr), nil
 }
+
+// routeOf returns the matched route template, falling back to the request path for unmatched routes.
+func routeOf(c *gin.Context) string {
+	if route := c.FullPath(); route != "" {
+		return route
+	}
+	return c.Request.URL.Path
+}
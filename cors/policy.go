@@ -0,0 +1,81 @@
+package cors
+
+import (
+	"context"
+	"os"
+	"sync/atomic"
+	"time"
+
+	l "github.com/piyushkumar96/generic-logger"
+)
+
+// CORSPolicy holds the active CORSHeaders; Store swaps them atomically while requests keep being served.
+type CORSPolicy struct {
+	current atomic.Pointer[CORSHeaders]
+}
+
+// NewCORSPolicy returns a policy initialised with corsHeaders.
+func NewCORSPolicy(corsHeaders *CORSHeaders) *CORSPolicy {
+	policy := &CORSPolicy{}
+	policy.Store(corsHeaders)
+	return policy
+}
+
+// Load returns the active headers; the returned value must be treated as read-only.
+func (p *CORSPolicy) Load() *CORSHeaders {
+	return p.current.Load()
+}
+
+// Store replaces the active headers.
+func (p *CORSPolicy) Store(corsHeaders *CORSHeaders) {
+	p.current.Store(corsHeaders)
+}
+
+// WatchCORSFile polls path every interval and, when the file changed and passes validation, swaps it into policy.
+// Runtime-only fields (AppMetrics, ViolationMetrics) are carried over from the previous headers. Invalid files are
+// logged and the current policy is kept. It blocks until ctx is done, so run it in its own goroutine.
+func WatchCORSFile(ctx context.Context, path string, policy *CORSPolicy, interval time.Duration) {
+	var lastModTime time.Time
+	var lastSize int64 = -1
+	if info, err := os.Stat(path); err == nil {
+		lastModTime, lastSize = info.ModTime(), info.Size()
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			if l.Logger != nil {
+				l.Logger.Error(ErrLoadConfig.Message, "code", ErrLoadConfig.Code, "path", path, "err", err.Error())
+			}
+			continue
+		}
+		if info.ModTime().Equal(lastModTime) && info.Size() == lastSize {
+			continue
+		}
+		lastModTime, lastSize = info.ModTime(), info.Size()
+
+		corsHeaders, err := LoadCORSHeadersFromFile(path)
+		if err != nil {
+			if l.Logger != nil {
+				l.Logger.Error(ErrInvalidConfig.Message, "code", ErrInvalidConfig.Code, "path", path, "err", err.Error())
+			}
+			continue
+		}
+		if previous := policy.Load(); previous != nil {
+			corsHeaders.AppMetrics = previous.AppMetrics
+			corsHeaders.ViolationMetrics = previous.ViolationMetrics
+		}
+		policy.Store(corsHeaders)
+		if l.Logger != nil {
+			l.Logger.Info("cors policy reloaded", "path", path)
+		}
+	}
+}
@@ -0,0 +1,17 @@
+package grpcinterceptor
+
+import cx "github.com/piyushkumar96/common-middlewares/context"
+
+// Metadata keys read from incoming calls; gRPC metadata keys are lower-case HTTP header names.
+const (
+	MetadataAuthorization = "authorization"
+	MetadataUserID        = "x-user-id"
+	MetadataDeploymentID  = "x-deployment-id"
+	MetadataUserAgent     = "user-agent"
+)
+
+// errorInfoDomain is the domain of the ErrorInfo detail attached to statuses built from app errors.
+const errorInfoDomain = "common-middlewares"
+
+// requestIDDetailKey is the ErrorInfo metadata key holding the request ID.
+const requestIDDetailKey = cx.ReqIDKey
@@ -0,0 +1,192 @@
+// Package grpcinterceptor provides gRPC server interceptors mirroring the gin middlewares: request IDs,
+// CtxMeta/TraceMeta/ResponseMeta, trace context propagation and authentication, with app errors mapped to
+// gRPC statuses.
+package grpcinterceptor
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	ae "github.com/piyushkumar96/app-error"
+	im "github.com/piyushkumar96/app-monitoring/interfaces"
+	"github.com/piyushkumar96/common-middlewares/authentication"
+	cx "github.com/piyushkumar96/common-middlewares/context"
+	"github.com/piyushkumar96/common-middlewares/trace"
+	l "github.com/piyushkumar96/generic-logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// InterceptorConfig configures the unary and stream server interceptors
+type InterceptorConfig struct {
+	// AppMetrics counts the error codes of app errors returned by handlers and is stored in ResponseMeta.
+	AppMetrics im.AppMetricsInterface
+	// RequestID selects the metadata keys and generator; nil uses the process-wide configuration.
+	RequestID *cx.RequestIDConfig
+	// Propagators extract the inbound trace context from metadata; empty means trace.DefaultPropagators().
+	Propagators []trace.Propagator
+	// BaggagePromotedKeys are W3C baggage keys copied into CtxMeta.Baggage, as in trace.TraceConfig.
+	BaggagePromotedKeys []string
+	// Auth, when set, validates the "authorization" metadata with the authentication package.
+	Auth *authentication.AuthConfig
+	// SkipAuthMethods are full method names (e.g. "/grpc.health.v1.Health/Check") served without auth.
+	SkipAuthMethods []string
+}
+
+// UnaryServerInterceptor populates the request context and authenticates unary calls.
+func UnaryServerInterceptor(config *InterceptorConfig) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, reqID := initCallContext(ctx, config, info.FullMethod)
+		_ = grpc.SetHeader(ctx, responseHeader(config, reqID))
+		if err := authenticate(ctx, config, info.FullMethod); err != nil {
+			return nil, err
+		}
+		resp, err := handler(ctx, req)
+		return resp, handleError(ctx, config, info.FullMethod, err)
+	}
+}
+
+// StreamServerInterceptor populates the request context and authenticates streaming calls; handlers read
+// the context from stream.Context().
+func StreamServerInterceptor(config *InterceptorConfig) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, reqID := initCallContext(ss.Context(), config, info.FullMethod)
+		_ = ss.SetHeader(responseHeader(config, reqID))
+		if err := authenticate(ctx, config, info.FullMethod); err != nil {
+			return err
+		}
+		err := handler(srv, &contextServerStream{ServerStream: ss, ctx: ctx})
+		return handleError(ctx, config, info.FullMethod, err)
+	}
+}
+
+// contextServerStream overrides the context of a grpc.ServerStream.
+type contextServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// initCallContext builds CtxMeta, ResponseMeta and TraceMeta from the incoming metadata, like trace.Trace
+// does from the HTTP headers, and returns the derived context and request ID.
+func initCallContext(ctx context.Context, config *InterceptorConfig, fullMethod string) (context.Context, string) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	header := http.Header{}
+	for key, values := range md {
+		for _, value := range values {
+			header.Add(key, value)
+		}
+	}
+
+	/** initialize context meta */
+	reqID := resolveRequestID(config, header)
+	ctxMeta := &cx.CtxMeta{
+		DeploymentID: header.Get(MetadataDeploymentID),
+		UserID:       header.Get(MetadataUserID),
+		ReqID:        reqID,
+		Path:         fullMethod,
+		UA:           header.Get(MetadataUserAgent),
+		Time:         time.Now(),
+	}
+	propagators := config.Propagators
+	if len(propagators) == 0 {
+		propagators = trace.DefaultPropagators()
+	}
+	inbound, ok := trace.ExtractSpanContext(header, propagators)
+	trace.ApplySpanContext(ctxMeta, inbound, ok)
+	ctx = context.WithValue(ctx, cx.CtxMetaKey, ctxMeta)
+	ctx = context.WithValue(ctx, cx.ReqIDKey, reqID)
+
+	/** initialize baggage */
+	baggage, err := trace.ParseBaggage(header.Get(trace.HeaderBaggage))
+	if err != nil && l.Logger != nil {
+		l.Logger.Debug(trace.ErrInvalidBaggage.Message, "code", trace.ErrInvalidBaggage.Code, "err", err.Error())
+	}
+	trace.PromoteBaggage(ctxMeta, baggage, config.BaggagePromotedKeys)
+	ctx = context.WithValue(ctx, cx.BaggageKey, baggage)
+
+	/** initialize response meta; there is no gin writer for gRPC calls */
+	ctx = context.WithValue(ctx, cx.ResponseMetaKey, &cx.ResponseMeta{AppMetrics: config.AppMetrics})
+
+	/** initialize trace meta */
+	key, traceMeta := cx.InitTraceMeta()
+	ctx = context.WithValue(ctx, key, traceMeta)
+	return ctx, reqID
+}
+
+// resolveRequestID returns the first valid inbound request ID, or generates one.
+func resolveRequestID(config *InterceptorConfig, header http.Header) string {
+	requestIDConfig := requestIDConfigOf(config)
+	for _, key := range requestIDConfig.InboundHeaders {
+		if value := header.Get(key); requestIDConfig.IsValid(value) {
+			return value
+		}
+	}
+	return requestIDConfig.NewRequestID()
+}
+
+// responseHeader echoes the request ID under the configured outbound keys.
+func responseHeader(config *InterceptorConfig, reqID string) metadata.MD {
+	md := metadata.MD{}
+	for _, key := range requestIDConfigOf(config).OutboundHeaders {
+		md.Set(strings.ToLower(key), reqID)
+	}
+	return md
+}
+
+func requestIDConfigOf(config *InterceptorConfig) *cx.RequestIDConfig {
+	if config.RequestID != nil {
+		return config.RequestID
+	}
+	return cx.GetRequestIDConfig()
+}
+
+// authenticate validates the authorization metadata with config.Auth and returns a status error on failure.
+func authenticate(ctx context.Context, config *InterceptorConfig, fullMethod string) error {
+	if config.Auth == nil || slices.Contains(config.SkipAuthMethods, fullMethod) {
+		return nil
+	}
+	var authorization string
+	if values := metadata.ValueFromIncomingContext(ctx, MetadataAuthorization); len(values) > 0 {
+		authorization = values[0]
+	}
+	if appErr := config.Auth.Validate(ctx, authorization); appErr != nil {
+		return handleError(ctx, config, fullMethod, appErr)
+	}
+	return nil
+}
+
+// handleError converts app errors to gRPC statuses, then logs and counts them.
+func handleError(ctx context.Context, config *InterceptorConfig, fullMethod string, err error) error {
+	appErr, err := toStatusError(ctx, err)
+	if appErr == nil {
+		return err
+	}
+	logAppError(ctx, fullMethod, appErr)
+	if config.AppMetrics != nil {
+		config.AppMetrics.LogMetrics(appErr.GetErrCodes())
+	}
+	return err
+}
+
+func logAppError(ctx context.Context, fullMethod string, appErr *ae.AppError) {
+	cx.AddTraceLog(ctx, appErr.GetMsg())
+	if l.Logger == nil {
+		return
+	}
+	fields := append(cx.LogFields(ctx), "method", fullMethod, "code", appErr.GetErrCode())
+	if appErr.GetErr() != nil {
+		fields = append(fields, "err", appErr.GetErr().Error())
+	}
+	if appErr.GetHTTPCode() >= http.StatusInternalServerError {
+		l.Logger.Error(appErr.GetMsg(), fields...)
+	} else {
+		l.Logger.Warn(appErr.GetMsg(), fields...)
+	}
+}
@@ -0,0 +1,263 @@
+package grpcinterceptor
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"slices"
+	"testing"
+
+	ae "github.com/piyushkumar96/app-error"
+	im "github.com/piyushkumar96/app-monitoring/interfaces"
+	"github.com/piyushkumar96/common-middlewares/authentication"
+	cx "github.com/piyushkumar96/common-middlewares/context"
+	"github.com/piyushkumar96/common-middlewares/trace"
+	l "github.com/piyushkumar96/generic-logger"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const (
+	testRequestID    = "req-123"
+	testTraceID      = "4bf92f3577b34da6a3ce929d0e0e4736"
+	testParentSpanID = "00f067aa0ba902b7"
+	testToken        = "test-token"
+	// testFailService makes the health server return errNotServed.
+	testFailService = "fail"
+)
+
+var errNotServed = ae.GetCustomErr("ERR_TEST_1001", "service not served", false)
+
+// healthServer records the request context seen by the handler of the last call.
+type healthServer struct {
+	healthpb.UnimplementedHealthServer
+	seen chan context.Context
+}
+
+func (s *healthServer) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	s.seen <- ctx
+	if req.GetService() == testFailService {
+		return nil, ae.GetAppErr(ctx, errors.New(errNotServed.Message), errNotServed, http.StatusNotFound)
+	}
+	return &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING}, nil
+}
+
+func (s *healthServer) Watch(req *healthpb.HealthCheckRequest, stream healthpb.Health_WatchServer) error {
+	s.seen <- stream.Context()
+	if req.GetService() == testFailService {
+		return ae.GetAppErr(stream.Context(), errors.New(errNotServed.Message), errNotServed, http.StatusNotFound)
+	}
+	return stream.Send(&healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING})
+}
+
+func newTestClient(t *testing.T, config *InterceptorConfig) (healthpb.HealthClient, *healthServer) {
+	t.Helper()
+	l.Init()
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(UnaryServerInterceptor(config)),
+		grpc.StreamInterceptor(StreamServerInterceptor(config)),
+	)
+	hs := &healthServer{seen: make(chan context.Context, 1)}
+	healthpb.RegisterHealthServer(server, hs)
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("dial bufconn: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return healthpb.NewHealthClient(conn), hs
+}
+
+// callPath performs one call over the unary (Check) or stream (Watch) path and returns the response header.
+type callPath func(ctx context.Context, client healthpb.HealthClient, service string) (metadata.MD, error)
+
+var callPaths = map[string]callPath{
+	"unary": func(ctx context.Context, client healthpb.HealthClient, service string) (metadata.MD, error) {
+		var header metadata.MD
+		_, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: service}, grpc.Header(&header))
+		return header, err
+	},
+	"stream": func(ctx context.Context, client healthpb.HealthClient, service string) (metadata.MD, error) {
+		stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{Service: service})
+		if err != nil {
+			return nil, err
+		}
+		_, err = stream.Recv()
+		header, _ := stream.Header()
+		return header, err
+	},
+}
+
+func TestInterceptorsPopulateContext(t *testing.T) {
+	for name, call := range callPaths {
+		t.Run(name, func(t *testing.T) {
+			client, hs := newTestClient(t, &InterceptorConfig{BaggagePromotedKeys: []string{"tenant"}})
+			ctx := metadata.AppendToOutgoingContext(context.Background(),
+				"x-request-id", testRequestID,
+				trace.HeaderTraceParent, "00-"+testTraceID+"-"+testParentSpanID+"-01",
+				trace.HeaderBaggage, "tenant=acme,region=eu",
+				MetadataUserID, "user-1")
+
+			header, err := call(ctx, client, "")
+			if err != nil {
+				t.Fatalf("call: %v", err)
+			}
+			if got := header.Get("x-request-id"); !slices.Equal(got, []string{testRequestID}) {
+				t.Errorf("response x-request-id = %v, want [%s]", got, testRequestID)
+			}
+
+			handlerCtx := <-hs.seen
+			meta := cx.GetContextMeta(handlerCtx)
+			if meta.ReqID != testRequestID || cx.GetRequestID(handlerCtx) != testRequestID {
+				t.Errorf("request id = %q / %q, want %q", meta.ReqID, cx.GetRequestID(handlerCtx), testRequestID)
+			}
+			if meta.TraceID != testTraceID || meta.ParentSpanID != testParentSpanID || !meta.Sampled {
+				t.Errorf("trace context = %s/%s sampled=%v, want %s/%s sampled", meta.TraceID, meta.ParentSpanID, meta.Sampled,
+					testTraceID, testParentSpanID)
+			}
+			if meta.UserID != "user-1" {
+				t.Errorf("user id = %q, want %q", meta.UserID, "user-1")
+			}
+			if len(meta.Baggage) != 1 || meta.Baggage["tenant"] != "acme" {
+				t.Errorf("promoted baggage = %v, want map[tenant:acme]", meta.Baggage)
+			}
+			if value, _ := trace.GetBaggage(handlerCtx).Get("region"); value != "eu" {
+				t.Errorf("baggage region = %q, want %q", value, "eu")
+			}
+			fields := cx.LogFields(handlerCtx)
+			if i := slices.Index(fields, interface{}(cx.BaggageFieldPrefix+"tenant")); i < 0 || fields[i+1] != "acme" {
+				t.Errorf("log fields %v miss the promoted baggage", fields)
+			}
+		})
+	}
+}
+
+func TestInterceptorsGenerateRequestIDWithoutGenerator(t *testing.T) {
+	for name, call := range callPaths {
+		t.Run(name, func(t *testing.T) {
+			config := &InterceptorConfig{RequestID: &cx.RequestIDConfig{OutboundHeaders: []string{"x-request-id"}}}
+			client, hs := newTestClient(t, config)
+
+			header, err := call(context.Background(), client, "")
+			if err != nil {
+				t.Fatalf("call: %v", err)
+			}
+			reqID := cx.GetRequestID(<-hs.seen)
+			if reqID == "" {
+				t.Fatal("no request id generated")
+			}
+			if got := header.Get("x-request-id"); !slices.Equal(got, []string{reqID}) {
+				t.Errorf("response x-request-id = %v, want [%s]", got, reqID)
+			}
+		})
+	}
+}
+
+func TestInterceptorsConvertAppErrors(t *testing.T) {
+	for name, call := range callPaths {
+		t.Run(name, func(t *testing.T) {
+			appMetrics := im.NewMockAppMetrics()
+			client, hs := newTestClient(t, &InterceptorConfig{AppMetrics: appMetrics})
+			ctx := metadata.AppendToOutgoingContext(context.Background(), "x-request-id", testRequestID)
+
+			_, err := call(ctx, client, testFailService)
+			<-hs.seen
+			st := status.Convert(err)
+			if st.Code() != codes.NotFound || st.Message() != errNotServed.Message {
+				t.Fatalf("status = %v %q, want %v %q", st.Code(), st.Message(), codes.NotFound, errNotServed.Message)
+			}
+			var info *errdetails.ErrorInfo
+			for _, detail := range st.Details() {
+				if d, ok := detail.(*errdetails.ErrorInfo); ok {
+					info = d
+				}
+			}
+			if info == nil || info.GetReason() != errNotServed.Code || info.GetMetadata()[requestIDDetailKey] != testRequestID {
+				t.Errorf("error info = %v, want reason %s and request id %s", info, errNotServed.Code, testRequestID)
+			}
+			if !slices.Contains(appMetrics.LogMetricsErrCodes, errNotServed.Code) {
+				t.Errorf("metrics codes = %v, want %s", appMetrics.LogMetricsErrCodes, errNotServed.Code)
+			}
+		})
+	}
+}
+
+func TestToStatusError(t *testing.T) {
+	var typedNil *ae.AppError
+	plain := errors.New("plain")
+	tests := []struct {
+		name       string
+		err        error
+		wantAppErr bool
+		wantErr    error
+		wantCode   codes.Code
+	}{
+		{name: "nil"},
+		{name: "typed nil app error", err: typedNil},
+		{name: "plain error", err: plain, wantErr: plain},
+		{name: "app error", err: ae.GetAppErr(context.Background(), errors.New("missing"), errNotServed, http.StatusNotFound),
+			wantAppErr: true, wantCode: codes.NotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			appErr, err := toStatusError(context.Background(), tt.err)
+			if (appErr != nil) != tt.wantAppErr {
+				t.Fatalf("app error = %v, want one %v", appErr, tt.wantAppErr)
+			}
+			switch {
+			case tt.wantCode != codes.OK:
+				if got := status.Code(err); got != tt.wantCode {
+					t.Errorf("code = %v, want %v", got, tt.wantCode)
+				}
+			case err != tt.wantErr:
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestInterceptorsAuthenticate(t *testing.T) {
+	tests := []struct {
+		name     string
+		token    string
+		skipAuth bool
+		want     codes.Code
+	}{
+		{name: "valid token", token: testToken, want: codes.OK},
+		{name: "missing token", token: "", want: codes.Unauthenticated},
+		{name: "wrong token", token: "other", want: codes.Unauthenticated},
+		{name: "skipped method", token: "", skipAuth: true, want: codes.OK},
+	}
+	for name, call := range callPaths {
+		for _, tt := range tests {
+			t.Run(name+"/"+tt.name, func(t *testing.T) {
+				config := &InterceptorConfig{Auth: &authentication.AuthConfig{Token: testToken}}
+				if tt.skipAuth {
+					config.SkipAuthMethods = []string{healthpb.Health_Check_FullMethodName, healthpb.Health_Watch_FullMethodName}
+				}
+				client, _ := newTestClient(t, config)
+				ctx := context.Background()
+				if tt.token != "" {
+					ctx = metadata.AppendToOutgoingContext(ctx, MetadataAuthorization, tt.token)
+				}
+
+				_, err := call(ctx, client, "")
+				if got := status.Code(err); got != tt.want {
+					t.Errorf("code = %v, want %v (err %v)", got, tt.want, err)
+				}
+			})
+		}
+	}
+}
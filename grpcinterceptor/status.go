@@ -0,0 +1,75 @@
+package grpcinterceptor
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	ae "github.com/piyushkumar96/app-error"
+	cx "github.com/piyushkumar96/common-middlewares/context"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CodeFromHTTPStatus maps the HTTP status of an app error to the closest gRPC code. Non-error statuses
+// (e.g. 200 or an unset 0) map to codes.Unknown, so converting an error never yields an OK (nil) status.
+func CodeFromHTTPStatus(httpStatus int) codes.Code {
+	switch httpStatus {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity, http.StatusRequestEntityTooLarge,
+		http.StatusUnsupportedMediaType:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	case http.StatusPreconditionFailed:
+		return codes.FailedPrecondition
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case 499: // client closed request
+		return codes.Canceled
+	case http.StatusNotImplemented:
+		return codes.Unimplemented
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case http.StatusGatewayTimeout, http.StatusRequestTimeout:
+		return codes.DeadlineExceeded
+	}
+	if httpStatus >= http.StatusInternalServerError {
+		return codes.Internal
+	}
+	return codes.Unknown
+}
+
+// StatusFromAppError converts appErr into a gRPC status: the code follows its HTTP status, the message is the
+// app error message, and an ErrorInfo detail carries the error code (reason) and the request ID of ctx.
+func StatusFromAppError(ctx context.Context, appErr *ae.AppError) *status.Status {
+	st := status.New(CodeFromHTTPStatus(appErr.GetHTTPCode()), appErr.GetMsg())
+	info := &errdetails.ErrorInfo{Reason: appErr.GetErrCode(), Domain: errorInfoDomain}
+	if reqID := cx.GetRequestID(ctx); reqID != "" {
+		info.Metadata = map[string]string{requestIDDetailKey: reqID}
+	}
+	if detailed, err := st.WithDetails(info); err == nil {
+		return detailed
+	}
+	return st
+}
+
+// toStatusError converts an *ae.AppError returned by a handler into a gRPC status error; other errors
+// (including ones that already carry a status) are returned unchanged. A typed nil *ae.AppError, as returned by
+// handlers declaring *ae.AppError results, is treated as success.
+func toStatusError(ctx context.Context, err error) (*ae.AppError, error) {
+	var appErr *ae.AppError
+	if err == nil || !errors.As(err, &appErr) {
+		return nil, err
+	}
+	if appErr == nil {
+		return nil, nil
+	}
+	return appErr, StatusFromAppError(ctx, appErr).Err()
+}
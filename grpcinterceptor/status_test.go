@@ -0,0 +1,52 @@
+package grpcinterceptor
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	ae "github.com/piyushkumar96/app-error"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCodeFromHTTPStatus(t *testing.T) {
+	tests := []struct {
+		httpStatus int
+		want       codes.Code
+	}{
+		{httpStatus: 0, want: codes.Unknown},
+		{httpStatus: http.StatusOK, want: codes.Unknown},
+		{httpStatus: http.StatusNoContent, want: codes.Unknown},
+		{httpStatus: http.StatusFound, want: codes.Unknown},
+		{httpStatus: http.StatusBadRequest, want: codes.InvalidArgument},
+		{httpStatus: http.StatusUnauthorized, want: codes.Unauthenticated},
+		{httpStatus: http.StatusNotFound, want: codes.NotFound},
+		{httpStatus: http.StatusTeapot, want: codes.Unknown},
+		{httpStatus: http.StatusInternalServerError, want: codes.Internal},
+		{httpStatus: http.StatusServiceUnavailable, want: codes.Unavailable},
+	}
+	for _, tt := range tests {
+		if got := CodeFromHTTPStatus(tt.httpStatus); got != tt.want {
+			t.Errorf("CodeFromHTTPStatus(%d) = %v, want %v", tt.httpStatus, got, tt.want)
+		}
+	}
+}
+
+func TestToStatusErrorNeverReturnsNil(t *testing.T) {
+	customErr := ae.GetCustomErr("ERR_TEST_1001", "test error", false)
+	for _, httpStatus := range []int{0, http.StatusOK, http.StatusNoContent} {
+		appErr := ae.GetAppErr(context.Background(), errors.New("cause"), customErr, httpStatus)
+		gotAppErr, err := toStatusError(context.Background(), appErr)
+		if gotAppErr != appErr {
+			t.Fatalf("status %d: app error not returned", httpStatus)
+		}
+		if err == nil {
+			t.Fatalf("status %d: error converted to a nil (OK) status", httpStatus)
+		}
+		if code := status.Code(err); code != codes.Unknown {
+			t.Errorf("status %d: code = %v, want %v", httpStatus, code, codes.Unknown)
+		}
+	}
+}
@@ -24,8 +24,7 @@ func OpenAPIValidatorRequest(router routers.Router) gin.HandlerFunc {
 		route, pathParams, err := router.FindRoute(gc.Request)
 		if err != nil {
 			appErr := ae.GetAppErr(ctx, err, ErrRouteNotFound, http.StatusNotFound)
-			cx.RespondJSON(gc, http.StatusNotFound, cx.MessageFailure(appErr.GetMsg()))
-			gc.Abort()
+			cx.RespondError(gc, appErr)
 			return
 		}
 
@@ -37,16 +36,14 @@ func OpenAPIValidatorRequest(router routers.Router) gin.HandlerFunc {
 					l.Logger.Error(ErrValidationUnexpectedType.Message, "meta", map[string]interface{}{"type": reflect.TypeOf(validationError)})
 				}
 				appErr := ae.GetAppErr(ctx, errors.New(ErrValidationUnexpectedType.Message), ErrValidationUnexpectedType, http.StatusInternalServerError)
-				cx.RespondJSON(gc, http.StatusInternalServerError, cx.MessageFailure(appErr.GetMsg()))
-				gc.Abort()
+				cx.RespondError(gc, appErr)
 				return
 			}
-			finalValidationErrorMsg := buildFinalValidationErrorMsg(ctx, &validationMultiError)
-			if finalValidationErrorMsg != "" {
-				customErr := ae.GetCustomErr(ErrValidationFailed.Code, finalValidationErrorMsg, false)
-				appErr := ae.GetAppErr(ctx, errors.New(customErr.Message), customErr, http.StatusBadRequest)
-				cx.RespondJSON(gc, http.StatusBadRequest, cx.MessageFailure(appErr.GetMsg()))
-				gc.Abort()
+			validationErrMsgs := buildValidationErrorMsgs(ctx, &validationMultiError)
+			if len(validationErrMsgs) > 0 {
+				customErr := ae.GetCustomErr(ErrValidationFailed.Code, strings.Join(validationErrMsgs, ", "), false)
+				appErr := ae.GetAppErr(ctx, errors.New(customErr.Message), customErr, http.StatusBadRequest, validationErrorDetails(validationErrMsgs))
+				cx.RespondError(gc, appErr)
 				return
 			}
 		}
@@ -68,8 +65,8 @@ func validateRequest(gc *gin.Context, pathParams map[string]string, route *route
 	return validationErr
 }
 
-// buildFinalValidationErrorMsg building the final request validation error msg basically combine all
-func buildFinalValidationErrorMsg(ctx context.Context, validationMultiError *openapi3.MultiError) string {
+// buildValidationErrorMsgs builds one message per request validation error
+func buildValidationErrorMsgs(ctx context.Context, validationMultiError *openapi3.MultiError) []string {
 	errsMsg := make([]string, 0)
 	for _, vme := range *validationMultiError {
 		reqValidationErr, ok := vme.(*openapi3filter.RequestError)
@@ -148,12 +145,19 @@ func buildFinalValidationErrorMsg(ctx context.Context, validationMultiError *ope
 
 	}
 
-	errsMsgStr := ""
-	if len(errsMsg) > 0 {
-		// Process validationErrs to get all errors as a single string
-		errsMsgStr = strings.ReplaceAll(strings.Join(errsMsg, ", "), `"`, "")
+	for i := range errsMsg {
+		errsMsg[i] = strings.ReplaceAll(errsMsg[i], `"`, "")
 	}
-	return errsMsgStr
+	return errsMsg
+}
+
+// validationErrorDetails turns the validation messages into the details of the error response.
+func validationErrorDetails(errsMsg []string) []cx.ErrorDetail {
+	details := make([]cx.ErrorDetail, 0, len(errsMsg))
+	for _, msg := range errsMsg {
+		details = append(details, cx.ErrorDetail{Message: msg})
+	}
+	return details
 }
 
 func handleMultiError(reqValidationErr *openapi3filter.RequestError, multiErr openapi3.MultiError) []string {
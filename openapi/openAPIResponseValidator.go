@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 	"reflect"
+	"strings"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/getkin/kin-openapi/openapi3filter"
@@ -54,8 +55,7 @@ func OpenAPIValidatorRequestAndResponse(router routers.Router) gin.HandlerFunc {
 		route, pathParams, err := router.FindRoute(gc.Request)
 		if err != nil {
 			appErr := ae.GetAppErr(ctx, err, ErrRouteNotFound, http.StatusNotFound)
-			cx.RespondJSON(gc, http.StatusNotFound, cx.MessageFailure(appErr.GetMsg()))
-			gc.Abort()
+			cx.RespondError(gc, appErr)
 			return
 		}
 
@@ -67,16 +67,14 @@ func OpenAPIValidatorRequestAndResponse(router routers.Router) gin.HandlerFunc {
 					l.Logger.Error(ErrValidationUnexpectedType.Message, "meta", map[string]interface{}{"type": reflect.TypeOf(validationError)})
 				}
 				appErr := ae.GetAppErr(ctx, errors.New(ErrValidationUnexpectedType.Message), ErrValidationUnexpectedType, http.StatusInternalServerError)
-				cx.RespondJSON(gc, http.StatusInternalServerError, cx.MessageFailure(appErr.GetMsg()))
-				gc.Abort()
+				cx.RespondError(gc, appErr)
 				return
 			}
-			finalValidationErrorMsg := buildFinalValidationErrorMsg(ctx, &validationMultiError)
-			if finalValidationErrorMsg != "" {
-				customErr := ae.GetCustomErr(ErrValidationFailed.Code, finalValidationErrorMsg, false)
-				appErr := ae.GetAppErr(ctx, errors.New(customErr.Message), customErr, http.StatusBadRequest)
-				cx.RespondJSON(gc, http.StatusBadRequest, cx.MessageFailure(appErr.GetMsg()))
-				gc.Abort()
+			validationErrMsgs := buildValidationErrorMsgs(ctx, &validationMultiError)
+			if len(validationErrMsgs) > 0 {
+				customErr := ae.GetCustomErr(ErrValidationFailed.Code, strings.Join(validationErrMsgs, ", "), false)
+				appErr := ae.GetAppErr(ctx, errors.New(customErr.Message), customErr, http.StatusBadRequest, validationErrorDetails(validationErrMsgs))
+				cx.RespondError(gc, appErr)
 				return
 			}
 		}
@@ -0,0 +1,13 @@
+package pubsubtrace
+
+import (
+	ae "github.com/piyushkumar96/app-error"
+)
+
+var (
+	// ErrWrapMessage is returned when a message cannot be wrapped with its trace attributes.
+	ErrWrapMessage = ae.GetCustomErr(
+		"ERR_PUBSUBTRACE_1001",
+		"failed to attach trace attributes to message",
+		false)
+)
@@ -0,0 +1,83 @@
+// Package main demonstrates pubsubtrace: an HTTP handler publishes an event and the consumer logs it with the
+// originating request ID and trace ID. An in-memory broker stands in for GCP Pub/Sub or PubNub.
+// Run: go run github.com/piyushkumar96/common-middlewares/pubsubtrace/examples
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	ae "github.com/piyushkumar96/app-error"
+	cx "github.com/piyushkumar96/common-middlewares/context"
+	"github.com/piyushkumar96/common-middlewares/pubsubtrace"
+	"github.com/piyushkumar96/common-middlewares/trace"
+	pubsub "github.com/piyushkumar96/generic-pubsub"
+)
+
+// memoryPubSub is a minimal pubsub.IPubSub delivering published messages to one listener channel.
+type memoryPubSub struct {
+	mu       sync.Mutex
+	seq      int
+	messages chan *pubsub.ConsumedMessage
+}
+
+func (m *memoryPubSub) Publish(_ context.Context, msg []byte) (pubsub.EventTxnData, *ae.AppError) {
+	m.mu.Lock()
+	m.seq++
+	id := strconv.Itoa(m.seq)
+	m.mu.Unlock()
+	m.messages <- &pubsub.ConsumedMessage{Data: msg, Meta: map[string]interface{}{"msg_id": id}}
+	return pubsub.EventTxnData{EventID: id, IsPublished: true, MessageSizeInBytes: len(msg)}, nil
+}
+
+func (m *memoryPubSub) PublishBatch(ctx context.Context, msgs [][]byte) ([]pubsub.EventTxnData, *ae.AppError) {
+	results := make([]pubsub.EventTxnData, 0, len(msgs))
+	for i, msg := range msgs {
+		result, _ := m.Publish(ctx, msg)
+		result.SequenceNo = i
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func (m *memoryPubSub) Listen(context.Context) *ae.AppError                          { return nil }
+func (m *memoryPubSub) ListenWithWait(context.Context, *sync.WaitGroup) *ae.AppError { return nil }
+func (m *memoryPubSub) AcknowledgeMessage(context.Context, string) *ae.AppError      { return nil }
+func (m *memoryPubSub) CheckHealth(context.Context) (bool, *ae.AppError)             { return true, nil }
+func (m *memoryPubSub) Teardown(context.Context)                                     {}
+
+func main() {
+	gin.SetMode(gin.ReleaseMode)
+	broker := &memoryPubSub{messages: make(chan *pubsub.ConsumedMessage, 16)}
+
+	// Consumer: rebuild the producer's request context for every message.
+	go func() {
+		for msg := range broker.messages {
+			ctx, payload, span := pubsubtrace.ConsumeContext(context.Background(), msg, nil)
+			meta := cx.GetContextMeta(ctx)
+			fmt.Printf("consumed %q request_id=%s trace_id=%s producer_span_id=%s\n", payload, meta.ReqID, meta.TraceID, meta.ParentSpanID)
+			span.End()
+		}
+	}()
+
+	r := gin.New()
+	r.Use(trace.Trace(nil))
+	r.POST("/orders", func(c *gin.Context) {
+		ctx := cx.GetRequestContext(c)
+		if _, appErr := pubsubtrace.Publish(ctx, broker, []byte(`{"event":"order_created"}`)); appErr != nil {
+			cx.RespondError(c, appErr)
+			return
+		}
+		cx.RespondSuccess(c, http.StatusAccepted, gin.H{"trace_id": cx.GetContextMeta(ctx).TraceID}, nil)
+	})
+
+	fmt.Println("Pub/Sub trace example: POST http://localhost:8087/orders")
+	if err := r.Run(":8087"); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,45 @@
+// Package pubsubtrace carries the request context (trace, request/user/deployment IDs, baggage) across
+// generic-pubsub messages. generic-pubsub only publishes raw bytes, so the attributes travel in a
+// trace.TracedMessage envelope around the payload.
+package pubsubtrace
+
+import (
+	"context"
+	"net/http"
+
+	ae "github.com/piyushkumar96/app-error"
+	"github.com/piyushkumar96/common-middlewares/trace"
+	pubsub "github.com/piyushkumar96/generic-pubsub"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// Publish wraps msg with the trace attributes of ctx and publishes it through client.
+func Publish(ctx context.Context, client pubsub.IPubSub, msg []byte) (pubsub.EventTxnData, *ae.AppError) {
+	wrapped, err := trace.WrapMessage(ctx, msg)
+	if err != nil {
+		return pubsub.EventTxnData{}, ae.GetAppErr(ctx, err, ErrWrapMessage, http.StatusInternalServerError)
+	}
+	return client.Publish(ctx, wrapped)
+}
+
+// PublishBatch wraps every message with the trace attributes of ctx and publishes them through client.
+func PublishBatch(ctx context.Context, client pubsub.IPubSub, msgs [][]byte) ([]pubsub.EventTxnData, *ae.AppError) {
+	wrapped := make([][]byte, 0, len(msgs))
+	for _, msg := range msgs {
+		w, err := trace.WrapMessage(ctx, msg)
+		if err != nil {
+			return nil, ae.GetAppErr(ctx, err, ErrWrapMessage, http.StatusInternalServerError)
+		}
+		wrapped = append(wrapped, w)
+	}
+	return client.PublishBatch(ctx, wrapped)
+}
+
+// ConsumeContext unwraps msg and rebuilds the producer's request context for processing it (see
+// trace.MessageContext). It returns the context, the original payload and the consumer span to End.
+// Messages published without Publish are returned as-is under a new trace.
+func ConsumeContext(parent context.Context, msg *pubsub.ConsumedMessage, config *trace.MessageContextConfig) (context.Context, []byte, oteltrace.Span) {
+	payload, attributes := trace.UnwrapMessage(msg.Data)
+	ctx, span := trace.MessageContext(parent, attributes, config)
+	return ctx, payload, span
+}
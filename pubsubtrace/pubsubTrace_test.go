@@ -0,0 +1,97 @@
+package pubsubtrace
+
+import (
+	"context"
+	"testing"
+
+	ae "github.com/piyushkumar96/app-error"
+	cx "github.com/piyushkumar96/common-middlewares/context"
+	"github.com/piyushkumar96/common-middlewares/trace"
+	pubsub "github.com/piyushkumar96/generic-pubsub"
+)
+
+const (
+	testTraceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	testSpanID  = "00f067aa0ba902b7"
+	testReqID   = "req-producer"
+)
+
+// capturingClient records published messages; the other IPubSub methods are not used by these tests.
+type capturingClient struct {
+	pubsub.IPubSub
+	published [][]byte
+}
+
+func (c *capturingClient) Publish(_ context.Context, msg []byte) (pubsub.EventTxnData, *ae.AppError) {
+	c.published = append(c.published, msg)
+	return pubsub.EventTxnData{}, nil
+}
+
+// newProducerContext returns a request context with request ID, trace context, baggage and TraceMeta.
+func newProducerContext(t *testing.T) context.Context {
+	t.Helper()
+	baggage, err := trace.ParseBaggage("tenant=acme")
+	if err != nil {
+		t.Fatalf("ParseBaggage: %v", err)
+	}
+	ctx := context.WithValue(context.Background(), cx.CtxMetaKey, &cx.CtxMeta{
+		ReqID:   testReqID,
+		TraceID: testTraceID,
+		SpanID:  testSpanID,
+		Sampled: true,
+	})
+	ctx = context.WithValue(ctx, cx.BaggageKey, baggage)
+	key, traceMeta := cx.InitTraceMeta()
+	return context.WithValue(ctx, key, traceMeta)
+}
+
+func TestPublishConsumeRoundTrip(t *testing.T) {
+	client := &capturingClient{}
+	if _, appErr := Publish(newProducerContext(t), client, []byte("order created")); appErr != nil {
+		t.Fatalf("Publish: %v", appErr)
+	}
+	if len(client.published) != 1 {
+		t.Fatalf("published %d messages, want 1", len(client.published))
+	}
+
+	ctx, payload, span := ConsumeContext(context.Background(), &pubsub.ConsumedMessage{Data: client.published[0]},
+		&trace.MessageContextConfig{BaggagePromotedKeys: []string{"tenant"}})
+	defer span.End()
+	if string(payload) != "order created" {
+		t.Errorf("payload = %q, want the original message", payload)
+	}
+	ctxMeta := cx.GetContextMeta(ctx)
+	if ctxMeta.ReqID != testReqID {
+		t.Errorf("request id = %q, want %q", ctxMeta.ReqID, testReqID)
+	}
+	if ctxMeta.TraceID != testTraceID || !ctxMeta.Sampled {
+		t.Errorf("trace = %s sampled %v, want %s sampled", ctxMeta.TraceID, ctxMeta.Sampled, testTraceID)
+	}
+	if ctxMeta.ParentSpanID == "" || ctxMeta.ParentSpanID == testSpanID || ctxMeta.SpanID == ctxMeta.ParentSpanID {
+		t.Errorf("spans = %s (parent %s), want a consumer span under the producer span", ctxMeta.SpanID, ctxMeta.ParentSpanID)
+	}
+	if value, ok := trace.GetBaggageValue(ctx, "tenant"); !ok || value != "acme" {
+		t.Errorf("baggage tenant = %q, %v; want acme", value, ok)
+	}
+	if got := ctxMeta.Baggage["tenant"]; got != "acme" {
+		t.Errorf("promoted baggage = %q, want acme", got)
+	}
+}
+
+func TestConsumeContextPlainMessage(t *testing.T) {
+	ctx, payload, span := ConsumeContext(context.Background(), &pubsub.ConsumedMessage{Data: []byte(`{"order_id":42}`)}, nil)
+	defer span.End()
+	if string(payload) != `{"order_id":42}` {
+		t.Errorf("payload = %q, want the message unchanged", payload)
+	}
+	ctxMeta := cx.GetContextMeta(ctx)
+	if ctxMeta.ReqID == "" || ctxMeta.TraceID == "" {
+		t.Errorf("context meta = %+v, want a generated request ID and a new trace", ctxMeta)
+	}
+	if ctxMeta.ParentSpanID != "" {
+		t.Errorf("parent span = %q, want a root span", ctxMeta.ParentSpanID)
+	}
+	if baggage := trace.GetBaggage(ctx).String(); baggage != "" {
+		t.Errorf("baggage = %q, want none", baggage)
+	}
+}
@@ -0,0 +1,43 @@
+// Package main demonstrates the responsedefaults middleware: Content-Type is only set when the handler did not set one.
+// Run: go run github.com/piyushkumar96/common-middlewares/responsedefaults/examples
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/piyushkumar96/common-middlewares/responsedefaults"
+)
+
+func main() {
+	gin.SetMode(gin.ReleaseMode)
+	r := gin.New()
+
+	r.Use(responsedefaults.ResponseDefaults(&responsedefaults.ResponseDefaultsConfig{
+		ContentType:       "application/json",
+		RouteContentTypes: map[string]string{"/docs": "text/html"},
+		MediaTypeParams:   map[string]string{"application/json": "charset=utf-8", "text/html": "charset=utf-8"},
+		// Headers defaulted by the response media type, whether the handler or the middleware set it.
+		MediaTypeHeaders: map[string]map[string]string{
+			"application/octet-stream": {"Content-Disposition": "attachment"},
+		},
+	}))
+
+	// Handler writes raw bytes without a Content-Type: the route default (text/html) is applied.
+	r.GET("/docs", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+		_, _ = c.Writer.WriteString("<h1>docs</h1>")
+	})
+
+	// Handler sets its own Content-Type: the default is left alone, and the octet-stream headers are added.
+	r.GET("/download", func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/octet-stream", []byte("binary"))
+	})
+
+	fmt.Println("Response defaults example: GET http://localhost:8085/docs or /download")
+	if err := r.Run(":8085"); err != nil {
+		log.Fatal(err)
+	}
+}
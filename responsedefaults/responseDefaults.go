@@ -0,0 +1,122 @@
+// Package responsedefaults sets default response headers (Content-Type and per media type headers) only when the
+// handler did not set them.
+package responsedefaults
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ResponseDefaultsConfig configures the ResponseDefaults middleware
+type ResponseDefaultsConfig struct {
+	// ContentType is the fallback media type for every route, e.g. "application/json". Empty disables the fallback.
+	ContentType string
+	// RouteContentTypes overrides ContentType per route template (gin FullPath, e.g. "/files/:id").
+	// An empty value disables defaulting for that route.
+	RouteContentTypes map[string]string
+	// MediaTypeParams appends parameters to a defaulted media type, e.g. "application/json": "charset=utf-8".
+	MediaTypeParams map[string]string
+	// MediaTypeHeaders sets default headers by the media type of the response (the handler's Content-Type or the
+	// defaulted one, parameters ignored), e.g. "text/csv": {"Content-Disposition": "attachment"}.
+	// Media types match case-insensitively; headers the handler already set are kept.
+	MediaTypeHeaders map[string]map[string]string
+}
+
+// ResponseDefaults returns a gin middleware that sets Content-Type and the media type headers right before the
+// headers are written, but only where the handler has not set them itself and the response can carry a body.
+func ResponseDefaults(config *ResponseDefaultsConfig) gin.HandlerFunc {
+	mediaTypeHeaders := make(map[string]map[string]string, len(config.MediaTypeHeaders))
+	for mediaType, headers := range config.MediaTypeHeaders {
+		mediaTypeHeaders[strings.ToLower(mediaType)] = headers
+	}
+	return func(gc *gin.Context) {
+		contentType := config.contentTypeFor(gc.FullPath())
+		if contentType == "" && len(mediaTypeHeaders) == 0 {
+			gc.Next()
+			return
+		}
+		gc.Writer = &defaultsWriter{ResponseWriter: gc.Writer, contentType: contentType, mediaTypeHeaders: mediaTypeHeaders}
+		gc.Next()
+	}
+}
+
+// contentTypeFor resolves the default content type (with media type params) for a route template.
+func (config *ResponseDefaultsConfig) contentTypeFor(route string) string {
+	contentType := config.ContentType
+	if routeContentType, ok := config.RouteContentTypes[route]; ok {
+		contentType = routeContentType
+	}
+	if contentType == "" {
+		return ""
+	}
+	if params, ok := config.MediaTypeParams[contentType]; ok && params != "" {
+		contentType += "; " + params
+	}
+	return contentType
+}
+
+// defaultsWriter applies the default headers once, right before the status line is sent.
+type defaultsWriter struct {
+	gin.ResponseWriter
+	contentType      string
+	mediaTypeHeaders map[string]map[string]string
+	applied          bool
+}
+
+func (w *defaultsWriter) applyDefaults() {
+	if w.applied || w.ResponseWriter.Written() {
+		return
+	}
+	w.applied = true
+	if !bodyAllowed(w.ResponseWriter.Status()) {
+		return
+	}
+	header := w.Header()
+	if header.Get("Content-Type") == "" && w.contentType != "" {
+		header.Set("Content-Type", w.contentType)
+	}
+	for key, value := range w.mediaTypeHeaders[mediaTypeOf(header.Get("Content-Type"))] {
+		if header.Get(key) == "" {
+			header.Set(key, value)
+		}
+	}
+}
+
+// mediaTypeOf returns the lower-cased media type of a Content-Type value, without parameters.
+func mediaTypeOf(contentType string) string {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	return strings.ToLower(strings.TrimSpace(mediaType))
+}
+
+func (w *defaultsWriter) WriteHeaderNow() {
+	w.applyDefaults()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *defaultsWriter) Write(b []byte) (int, error) {
+	w.applyDefaults()
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *defaultsWriter) WriteString(s string) (int, error) {
+	w.applyDefaults()
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *defaultsWriter) Flush() {
+	w.applyDefaults()
+	w.ResponseWriter.Flush()
+}
+
+// bodyAllowed reports whether a response with the given status may carry a body (RFC 9110).
+func bodyAllowed(status int) bool {
+	switch {
+	case status >= 100 && status < 200:
+		return false
+	case status == http.StatusNoContent, status == http.StatusNotModified:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,102 @@
+package responsedefaults
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newTestRouter(config *ResponseDefaultsConfig) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(ResponseDefaults(config))
+	// raw writes the body without a Content-Type.
+	raw := func(c *gin.Context) {
+		c.Status(http.StatusOK)
+		_, _ = c.Writer.WriteString("body")
+	}
+	r.GET("/raw", raw)
+	r.GET("/docs", raw)
+	r.GET("/stream", raw)
+	r.GET("/csv", func(c *gin.Context) {
+		c.Header("Content-Disposition", `attachment; filename="report.csv"`)
+		c.Data(http.StatusOK, "Text/CSV; charset=utf-8", []byte("a,b"))
+	})
+	r.GET("/download", func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/octet-stream", []byte("binary"))
+	})
+	r.GET("/empty", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	return r
+}
+
+func serve(r *gin.Engine, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	return w
+}
+
+func TestResponseDefaults(t *testing.T) {
+	config := &ResponseDefaultsConfig{
+		ContentType:       "application/json",
+		RouteContentTypes: map[string]string{"/docs": "text/html", "/stream": ""},
+		MediaTypeParams:   map[string]string{"application/json": "charset=utf-8"},
+		MediaTypeHeaders: map[string]map[string]string{
+			"application/json":         {"X-Content-Type-Options": "nosniff"},
+			"text/csv":                 {"Content-Disposition": "attachment", "Cache-Control": "no-store"},
+			"application/octet-stream": {"Content-Disposition": "attachment"},
+		},
+	}
+	tests := []struct {
+		path   string
+		want   map[string]string
+		status int
+	}{
+		{path: "/raw", status: http.StatusOK, want: map[string]string{
+			"Content-Type":           "application/json; charset=utf-8",
+			"X-Content-Type-Options": "nosniff",
+		}},
+		{path: "/docs", status: http.StatusOK, want: map[string]string{
+			"Content-Type":           "text/html",
+			"X-Content-Type-Options": "",
+		}},
+		{path: "/stream", status: http.StatusOK, want: map[string]string{
+			"Content-Type": "",
+		}},
+		{path: "/csv", status: http.StatusOK, want: map[string]string{
+			"Content-Type":        "Text/CSV; charset=utf-8",
+			"Content-Disposition": `attachment; filename="report.csv"`,
+			"Cache-Control":       "no-store",
+		}},
+		{path: "/download", status: http.StatusOK, want: map[string]string{
+			"Content-Type":        "application/octet-stream",
+			"Content-Disposition": "attachment",
+		}},
+		{path: "/empty", status: http.StatusNoContent, want: map[string]string{
+			"Content-Type":           "",
+			"X-Content-Type-Options": "",
+		}},
+	}
+	r := newTestRouter(config)
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := serve(r, tt.path)
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d", w.Code, tt.status)
+			}
+			for header, value := range tt.want {
+				if got := w.Header().Get(header); got != value {
+					t.Errorf("%s = %q, want %q", header, got, value)
+				}
+			}
+		})
+	}
+}
+
+func TestResponseDefaultsKeepsHandlerContentType(t *testing.T) {
+	r := newTestRouter(&ResponseDefaultsConfig{ContentType: "application/json"})
+	if got := serve(r, "/download").Header().Get("Content-Type"); got != "application/octet-stream" {
+		t.Errorf("Content-Type = %q, want the handler's application/octet-stream", got)
+	}
+}
@@ -0,0 +1,41 @@
+package securityheaders
+
+// NoncePlaceholder is replaced in ContentSecurityPolicy/ContentSecurityPolicyReportOnly with the per-request nonce.
+const NoncePlaceholder = "{{nonce}}"
+
+// nonceSize is the number of random bytes in a CSP nonce (base64 encoded in the header).
+const nonceSize = 16
+
+const (
+	HeaderStrictTransportSecurity         = "Strict-Transport-Security"
+	HeaderContentSecurityPolicy           = "Content-Security-Policy"
+	HeaderContentSecurityPolicyReportOnly = "Content-Security-Policy-Report-Only"
+	HeaderXContentTypeOptions             = "X-Content-Type-Options"
+	HeaderXFrameOptions                   = "X-Frame-Options"
+	HeaderReferrerPolicy                  = "Referrer-Policy"
+	HeaderPermissionsPolicy               = "Permissions-Policy"
+	HeaderCrossOriginOpenerPolicy         = "Cross-Origin-Opener-Policy"
+	HeaderCrossOriginEmbedderPolicy       = "Cross-Origin-Embedder-Policy"
+)
+
+const (
+	// MediaTypeCSPReport is sent by browsers for the report-uri directive.
+	MediaTypeCSPReport = "application/csp-report"
+	// MediaTypeReportsJSON is sent by browsers for the Reporting API (report-to directive).
+	MediaTypeReportsJSON = "application/reports+json"
+
+	HeaderReportingEndpoints = "Reporting-Endpoints"
+	// ReportingEndpointName is the report-to group name emitted when CSPReportURI is configured.
+	ReportingEndpointName = "csp-endpoint"
+
+	reportTypeCSPViolation = "csp-violation"
+)
+
+// knownDirectives are the CSP directives used as metric labels; anything else is counted as "other".
+var knownDirectives = map[string]struct{}{
+	"default-src": {}, "script-src": {}, "script-src-elem": {}, "script-src-attr": {}, "style-src": {},
+	"style-src-elem": {}, "style-src-attr": {}, "img-src": {}, "font-src": {}, "connect-src": {}, "media-src": {},
+	"object-src": {}, "frame-src": {}, "child-src": {}, "worker-src": {}, "manifest-src": {}, "prefetch-src": {},
+	"base-uri": {}, "form-action": {}, "frame-ancestors": {}, "navigate-to": {}, "require-trusted-types-for": {},
+	"trusted-types": {}, "upgrade-insecure-requests": {}, "sandbox": {},
+}
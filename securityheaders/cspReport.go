@@ -0,0 +1,341 @@
+package securityheaders
+
+import (
+	"container/list"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	ae "github.com/piyushkumar96/app-error"
+	im "github.com/piyushkumar96/app-monitoring/interfaces"
+	cx "github.com/piyushkumar96/common-middlewares/context"
+	l "github.com/piyushkumar96/generic-logger"
+)
+
+// CSPViolationMetricsInterface records CSP violations on a metric labeled by error code and directive.
+type CSPViolationMetricsInterface interface {
+	LogViolation(code, directive string)
+}
+
+// CSPReportConfig configures the CSPReportHandler
+type CSPReportConfig struct {
+	// AppMetrics, when set, counts every forwarded violation under ErrCSPViolation.Code.
+	AppMetrics im.AppMetricsInterface
+	// ViolationMetrics, when set, records every forwarded violation with its directive as a metric label.
+	ViolationMetrics CSPViolationMetricsInterface
+	// DedupWindow drops identical violations (document, directive, blocked URL) seen again within the window.
+	DedupWindow time.Duration
+	// MaxReportsPerClient is the number of reports accepted per client IP per RateLimitWindow (0 disables the limit).
+	MaxReportsPerClient int
+	RateLimitWindow     time.Duration
+	// MaxBodyBytes caps the request body size.
+	MaxBodyBytes int64
+	// MaxTrackedKeys bounds the dedup and rate-limit tables. Past this size the dedup table evicts its oldest keys;
+	// the rate-limit table is pruned (and reset if still full).
+	MaxTrackedKeys int
+}
+
+// CSPViolation is the normalized form of a CSP report (report-uri or Reporting API).
+type CSPViolation struct {
+	DocumentURL        string `json:"document_url"`
+	Referrer           string `json:"referrer,omitempty"`
+	BlockedURL         string `json:"blocked_url"`
+	EffectiveDirective string `json:"effective_directive"`
+	OriginalPolicy     string `json:"original_policy,omitempty"`
+	Disposition        string `json:"disposition,omitempty"`
+	SourceFile         string `json:"source_file,omitempty"`
+	LineNumber         int    `json:"line_number,omitempty"`
+	ColumnNumber       int    `json:"column_number,omitempty"`
+	StatusCode         int    `json:"status_code,omitempty"`
+	Sample             string `json:"sample,omitempty"`
+	UserAgent          string `json:"user_agent,omitempty"`
+}
+
+// legacyCSPReport is the application/csp-report payload sent for report-uri.
+type legacyCSPReport struct {
+	Body struct {
+		DocumentURI        string `json:"document-uri"`
+		Referrer           string `json:"referrer"`
+		BlockedURI         string `json:"blocked-uri"`
+		ViolatedDirective  string `json:"violated-directive"`
+		EffectiveDirective string `json:"effective-directive"`
+		OriginalPolicy     string `json:"original-policy"`
+		Disposition        string `json:"disposition"`
+		SourceFile         string `json:"source-file"`
+		LineNumber         int    `json:"line-number"`
+		ColumnNumber       int    `json:"column-number"`
+		StatusCode         int    `json:"status-code"`
+		ScriptSample       string `json:"script-sample"`
+	} `json:"csp-report"`
+}
+
+// reportingAPIReport is one entry of the application/reports+json payload sent for report-to.
+type reportingAPIReport struct {
+	Type      string `json:"type"`
+	URL       string `json:"url"`
+	UserAgent string `json:"user_agent"`
+	Body      struct {
+		DocumentURL        string `json:"documentURL"`
+		Referrer           string `json:"referrer"`
+		BlockedURL         string `json:"blockedURL"`
+		EffectiveDirective string `json:"effectiveDirective"`
+		OriginalPolicy     string `json:"originalPolicy"`
+		Disposition        string `json:"disposition"`
+		SourceFile         string `json:"sourceFile"`
+		LineNumber         int    `json:"lineNumber"`
+		ColumnNumber       int    `json:"columnNumber"`
+		StatusCode         int    `json:"statusCode"`
+		Sample             string `json:"sample"`
+	} `json:"body"`
+}
+
+// NewDefaultCSPReportConfig returns a collector config with conservative limits.
+func NewDefaultCSPReportConfig() *CSPReportConfig {
+	return &CSPReportConfig{
+		DedupWindow:         time.Minute,
+		MaxReportsPerClient: 20,
+		RateLimitWindow:     time.Minute,
+		MaxBodyBytes:        64 * 1024,
+		MaxTrackedKeys:      10000,
+	}
+}
+
+// CSPReportHandler returns a gin handler that collects CSP violation reports (application/csp-report and
+// application/reports+json), normalizes, deduplicates and rate-limits them, and forwards them to the logger and metrics.
+// Mount it on the path used in SecurityHeadersConfig.CSPReportURI, e.g. r.POST("/csp-report", CSPReportHandler(cfg)).
+func CSPReportHandler(config *CSPReportConfig) gin.HandlerFunc {
+	if config == nil {
+		config = NewDefaultCSPReportConfig()
+	}
+	dedup := newExpiringSet(config.MaxTrackedKeys)
+	limiter := newWindowLimiter(config.MaxTrackedKeys)
+
+	return func(gc *gin.Context) {
+		ctx := cx.GetRequestContext(gc)
+		now := time.Now()
+
+		if config.MaxReportsPerClient > 0 && !limiter.allow(gc.ClientIP(), config.MaxReportsPerClient, config.RateLimitWindow, now) {
+			appErr := ae.GetAppErr(ctx, errors.New(ErrCSPReportRateLimited.Message), ErrCSPReportRateLimited, http.StatusTooManyRequests)
+			cx.RespondError(gc, appErr)
+			return
+		}
+
+		mediaType, _, _ := mime.ParseMediaType(gc.GetHeader(string(cx.HeaderContentType)))
+		body := io.Reader(gc.Request.Body)
+		if config.MaxBodyBytes > 0 {
+			body = http.MaxBytesReader(gc.Writer, gc.Request.Body, config.MaxBodyBytes)
+		}
+		payload, err := io.ReadAll(body)
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			appErr := ae.GetAppErr(ctx, err, ErrCSPReportInvalid, http.StatusBadRequest)
+			if errors.As(err, &maxBytesErr) {
+				appErr = ae.GetAppErr(ctx, err, ErrCSPReportTooLarge, http.StatusRequestEntityTooLarge)
+			}
+			cx.RespondError(gc, appErr)
+			return
+		}
+
+		var violations []CSPViolation
+		switch mediaType {
+		case MediaTypeCSPReport:
+			violations, err = parseLegacyCSPReport(payload)
+		case MediaTypeReportsJSON:
+			violations, err = parseReportingAPIReports(payload)
+		default:
+			appErr := ae.GetAppErr(ctx, errors.New(ErrCSPReportUnsupportedType.Message), ErrCSPReportUnsupportedType, http.StatusUnsupportedMediaType)
+			cx.RespondError(gc, appErr)
+			return
+		}
+		if err != nil {
+			appErr := ae.GetAppErr(ctx, err, ErrCSPReportInvalid, http.StatusBadRequest)
+			cx.RespondError(gc, appErr)
+			return
+		}
+
+		for i := range violations {
+			v := &violations[i]
+			if v.UserAgent == "" {
+				v.UserAgent = gc.GetHeader("User-Agent")
+			}
+			key := strings.Join([]string{v.DocumentURL, v.EffectiveDirective, v.BlockedURL, v.Disposition}, "|")
+			if config.DedupWindow > 0 && !dedup.add(key, config.DedupWindow, now) {
+				continue
+			}
+			forwardViolation(config, v)
+		}
+		gc.AbortWithStatus(http.StatusNoContent)
+	}
+}
+
+func parseLegacyCSPReport(payload []byte) ([]CSPViolation, error) {
+	var report legacyCSPReport
+	if err := json.Unmarshal(payload, &report); err != nil {
+		return nil, err
+	}
+	b := report.Body
+	directive := b.EffectiveDirective
+	if fields := strings.Fields(b.ViolatedDirective); directive == "" && len(fields) > 0 {
+		// violated-directive may carry the source list, e.g. "script-src 'self'"
+		directive = fields[0]
+	}
+	return []CSPViolation{{
+		DocumentURL:        b.DocumentURI,
+		Referrer:           b.Referrer,
+		BlockedURL:         b.BlockedURI,
+		EffectiveDirective: directive,
+		OriginalPolicy:     b.OriginalPolicy,
+		Disposition:        b.Disposition,
+		SourceFile:         b.SourceFile,
+		LineNumber:         b.LineNumber,
+		ColumnNumber:       b.ColumnNumber,
+		StatusCode:         b.StatusCode,
+		Sample:             b.ScriptSample,
+	}}, nil
+}
+
+func parseReportingAPIReports(payload []byte) ([]CSPViolation, error) {
+	var reports []reportingAPIReport
+	if err := json.Unmarshal(payload, &reports); err != nil {
+		return nil, err
+	}
+	violations := make([]CSPViolation, 0, len(reports))
+	for _, r := range reports {
+		if r.Type != reportTypeCSPViolation {
+			continue
+		}
+		documentURL := r.Body.DocumentURL
+		if documentURL == "" {
+			documentURL = r.URL
+		}
+		violations = append(violations, CSPViolation{
+			DocumentURL:        documentURL,
+			Referrer:           r.Body.Referrer,
+			BlockedURL:         r.Body.BlockedURL,
+			EffectiveDirective: r.Body.EffectiveDirective,
+			OriginalPolicy:     r.Body.OriginalPolicy,
+			Disposition:        r.Body.Disposition,
+			SourceFile:         r.Body.SourceFile,
+			LineNumber:         r.Body.LineNumber,
+			ColumnNumber:       r.Body.ColumnNumber,
+			StatusCode:         r.Body.StatusCode,
+			Sample:             r.Body.Sample,
+			UserAgent:          r.UserAgent,
+		})
+	}
+	return violations, nil
+}
+
+func forwardViolation(config *CSPReportConfig, v *CSPViolation) {
+	directive := metricDirective(v.EffectiveDirective)
+	if l.Logger != nil {
+		l.Logger.Warn(ErrCSPViolation.Message, "code", ErrCSPViolation.Code, "directive", directive, "violation", v)
+	}
+	if config.AppMetrics != nil {
+		config.AppMetrics.LogMetrics([]string{ErrCSPViolation.Code})
+	}
+	if config.ViolationMetrics != nil {
+		config.ViolationMetrics.LogViolation(ErrCSPViolation.Code, directive)
+	}
+}
+
+// metricDirective bounds the metric label cardinality to the known CSP fetch/navigation directives.
+func metricDirective(directive string) string {
+	directive = strings.ToLower(strings.TrimSpace(directive))
+	if _, ok := knownDirectives[directive]; ok {
+		return directive
+	}
+	return "other"
+}
+
+// expiringSet remembers keys for a window; add reports false for keys still inside their window. Keys are kept in
+// expiry order, so a full set evicts expired keys first and then the oldest ones, never the whole table.
+type expiringSet struct {
+	mu      sync.Mutex
+	maxKeys int
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type expiringEntry struct {
+	key    string
+	expiry time.Time
+}
+
+func newExpiringSet(maxKeys int) *expiringSet {
+	return &expiringSet{maxKeys: maxKeys, order: list.New(), entries: make(map[string]*list.Element)}
+}
+
+func (s *expiringSet) add(key string, window time.Duration, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if element, ok := s.entries[key]; ok {
+		entry := element.Value.(*expiringEntry)
+		if now.Before(entry.expiry) {
+			return false
+		}
+		entry.expiry = now.Add(window)
+		s.order.MoveToBack(element)
+		return true
+	}
+	for front := s.order.Front(); front != nil && !now.Before(front.Value.(*expiringEntry).expiry); front = s.order.Front() {
+		s.remove(front)
+	}
+	for s.maxKeys > 0 && s.order.Len() >= s.maxKeys {
+		s.remove(s.order.Front())
+	}
+	s.entries[key] = s.order.PushBack(&expiringEntry{key: key, expiry: now.Add(window)})
+	return true
+}
+
+func (s *expiringSet) remove(element *list.Element) {
+	s.order.Remove(element)
+	delete(s.entries, element.Value.(*expiringEntry).key)
+}
+
+// windowLimiter is a fixed-window counter per key.
+type windowLimiter struct {
+	mu      sync.Mutex
+	maxKeys int
+	windows map[string]*limiterWindow
+}
+
+type limiterWindow struct {
+	start time.Time
+	count int
+}
+
+func newWindowLimiter(maxKeys int) *windowLimiter {
+	return &windowLimiter{maxKeys: maxKeys, windows: make(map[string]*limiterWindow)}
+}
+
+func (wl *windowLimiter) allow(key string, limit int, window time.Duration, now time.Time) bool {
+	wl.mu.Lock()
+	defer wl.mu.Unlock()
+	w, ok := wl.windows[key]
+	if !ok || now.Sub(w.start) >= window {
+		if !ok && wl.maxKeys > 0 && len(wl.windows) >= wl.maxKeys {
+			for k, old := range wl.windows {
+				if now.Sub(old.start) >= window {
+					delete(wl.windows, k)
+				}
+			}
+			if len(wl.windows) >= wl.maxKeys {
+				wl.windows = make(map[string]*limiterWindow)
+			}
+		}
+		w = &limiterWindow{start: now}
+		wl.windows[key] = w
+	}
+	if w.count >= limit {
+		return false
+	}
+	w.count++
+	return true
+}
@@ -0,0 +1,170 @@
+package securityheaders
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	im "github.com/piyushkumar96/app-monitoring/interfaces"
+	cx "github.com/piyushkumar96/common-middlewares/context"
+)
+
+const (
+	testLegacyReport = `{"csp-report":{"document-uri":"https://app.example.com/page","blocked-uri":"https://cdn.evil.test/x.js",` +
+		`"violated-directive":"script-src 'self'","original-policy":"script-src 'self'","disposition":"enforce"}}`
+	testReportingAPIReports = `[` +
+		`{"type":"csp-violation","url":"https://app.example.com/page","user_agent":"ua-test","body":{` +
+		`"blockedURL":"https://cdn.evil.test/x.css","effectiveDirective":"style-src-elem","disposition":"report"}},` +
+		`{"type":"deprecation","url":"https://app.example.com/page","body":{}}]`
+)
+
+// directiveRecorder collects the directives passed to LogViolation.
+type directiveRecorder struct {
+	directives []string
+}
+
+func (r *directiveRecorder) LogViolation(_, directive string) {
+	r.directives = append(r.directives, directive)
+}
+
+func newReportRouter(config *CSPReportConfig) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/csp-report", CSPReportHandler(config))
+	return r
+}
+
+func postReport(r *gin.Engine, contentType, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/csp-report", strings.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func errorCodeOf(t *testing.T, w *httptest.ResponseRecorder) string {
+	t.Helper()
+	var failure cx.FailureResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &failure); err != nil || failure.Error == nil {
+		t.Fatalf("decode failure body %q: %v", w.Body.String(), err)
+	}
+	return failure.Error.Code
+}
+
+func TestCSPReportFormats(t *testing.T) {
+	tests := []struct {
+		name          string
+		contentType   string
+		body          string
+		wantDirective []string
+	}{
+		{name: "report-uri", contentType: MediaTypeCSPReport, body: testLegacyReport, wantDirective: []string{"script-src"}},
+		{name: "reporting api", contentType: MediaTypeReportsJSON + "; charset=utf-8", body: testReportingAPIReports,
+			wantDirective: []string{"style-src-elem"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			appMetrics := im.NewMockAppMetrics()
+			recorder := &directiveRecorder{}
+			config := NewDefaultCSPReportConfig()
+			config.AppMetrics = appMetrics
+			config.ViolationMetrics = recorder
+
+			w := postReport(newReportRouter(config), tt.contentType, tt.body)
+			if w.Code != http.StatusNoContent {
+				t.Fatalf("status = %d, want %d (body %s)", w.Code, http.StatusNoContent, w.Body.String())
+			}
+			if !slices.Equal(recorder.directives, tt.wantDirective) {
+				t.Errorf("directives = %v, want %v", recorder.directives, tt.wantDirective)
+			}
+			if !slices.Equal(appMetrics.LogMetricsErrCodes, []string{ErrCSPViolation.Code}) {
+				t.Errorf("metrics codes = %v, want [%s]", appMetrics.LogMetricsErrCodes, ErrCSPViolation.Code)
+			}
+		})
+	}
+}
+
+func TestCSPReportRejections(t *testing.T) {
+	config := NewDefaultCSPReportConfig()
+	config.MaxBodyBytes = 64
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		wantStatus  int
+		wantCode    string
+	}{
+		{name: "oversized body", contentType: MediaTypeCSPReport, body: testLegacyReport,
+			wantStatus: http.StatusRequestEntityTooLarge, wantCode: ErrCSPReportTooLarge.Code},
+		{name: "malformed body", contentType: MediaTypeCSPReport, body: `{"csp-report":`,
+			wantStatus: http.StatusBadRequest, wantCode: ErrCSPReportInvalid.Code},
+		{name: "unsupported type", contentType: "text/plain", body: "report",
+			wantStatus: http.StatusUnsupportedMediaType, wantCode: ErrCSPReportUnsupportedType.Code},
+	}
+	r := newReportRouter(config)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := postReport(r, tt.contentType, tt.body)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if code := errorCodeOf(t, w); code != tt.wantCode {
+				t.Errorf("code = %s, want %s", code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestCSPReportDedup(t *testing.T) {
+	recorder := &directiveRecorder{}
+	config := NewDefaultCSPReportConfig()
+	config.ViolationMetrics = recorder
+	r := newReportRouter(config)
+
+	for i := 0; i < 3; i++ {
+		if w := postReport(r, MediaTypeCSPReport, testLegacyReport); w.Code != http.StatusNoContent {
+			t.Fatalf("report %d: status = %d", i, w.Code)
+		}
+	}
+	postReport(r, MediaTypeReportsJSON, testReportingAPIReports)
+	if want := []string{"script-src", "style-src-elem"}; !slices.Equal(recorder.directives, want) {
+		t.Errorf("forwarded directives = %v, want %v", recorder.directives, want)
+	}
+}
+
+func TestExpiringSet(t *testing.T) {
+	now := time.Now()
+	set := newExpiringSet(3)
+
+	if !set.add("a", time.Minute, now) || set.add("a", time.Minute, now.Add(time.Second)) {
+		t.Fatal("duplicate inside the window was not dropped")
+	}
+	if !set.add("a", time.Minute, now.Add(time.Minute)) {
+		t.Fatal("key was not accepted again after its window")
+	}
+
+	// a flood of distinct keys evicts only the oldest ones: recent keys stay deduplicated
+	flood := now.Add(2 * time.Minute)
+	for i := 0; i < 10; i++ {
+		if !set.add(fmt.Sprintf("flood-%d", i), time.Minute, flood) {
+			t.Fatalf("new key flood-%d dropped", i)
+		}
+	}
+	if got := len(set.entries); got != 3 {
+		t.Fatalf("tracked keys = %d, want 3", got)
+	}
+	for i := 7; i < 10; i++ {
+		if set.add(fmt.Sprintf("flood-%d", i), time.Minute, flood.Add(time.Second)) {
+			t.Errorf("recent key flood-%d was forgotten", i)
+		}
+	}
+	if !set.add("flood-0", time.Minute, flood.Add(time.Second)) {
+		t.Error("oldest key flood-0 was not evicted")
+	}
+}
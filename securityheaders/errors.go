@@ -0,0 +1,49 @@
+package securityheaders
+
+import (
+	ae "github.com/piyushkumar96/app-error"
+)
+
+var (
+	// ErrNonceGeneration is returned when the CSP nonce cannot be generated from crypto/rand.
+	ErrNonceGeneration = ae.GetCustomErr(
+		"ERR_SECHDR_1001",
+		"failed to generate csp nonce",
+		false)
+
+	// ErrCSPViolation is logged (with the directive as a field) and counted for every forwarded CSP violation report.
+	ErrCSPViolation = ae.GetCustomErr(
+		"ERR_SECHDR_1002",
+		"content security policy violation reported",
+		false)
+
+	// ErrCSPReportUnsupportedType is returned when the report is neither application/csp-report nor application/reports+json.
+	ErrCSPReportUnsupportedType = ae.GetCustomErr(
+		"ERR_SECHDR_1003",
+		"unsupported csp report content type",
+		false)
+
+	// ErrCSPReportInvalid is returned when the report body cannot be read or parsed.
+	ErrCSPReportInvalid = ae.GetCustomErr(
+		"ERR_SECHDR_1004",
+		"invalid csp report payload",
+		false)
+
+	// ErrCSPReportRateLimited is returned when a client sends more reports than allowed per window.
+	ErrCSPReportRateLimited = ae.GetCustomErr(
+		"ERR_SECHDR_1005",
+		"too many csp reports",
+		false)
+
+	// ErrCSPReportTooLarge is returned when the report body exceeds CSPReportConfig.MaxBodyBytes.
+	ErrCSPReportTooLarge = ae.GetCustomErr(
+		"ERR_SECHDR_1006",
+		"csp report payload too large",
+		false)
+
+	// ErrInvalidConfig prefixes every SecurityHeadersConfig validation error.
+	ErrInvalidConfig = ae.GetCustomErr(
+		"ERR_SECHDR_1007",
+		"invalid security headers configuration",
+		false)
+)
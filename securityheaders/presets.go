@@ -0,0 +1,45 @@
+package securityheaders
+
+// APIPreset returns headers for JSON APIs that never render HTML: nothing may be loaded, framed or embedded.
+func APIPreset() *SecurityHeadersConfig {
+	return &SecurityHeadersConfig{
+		StrictTransportSecurity:   "max-age=63072000; includeSubDomains",
+		ContentSecurityPolicy:     "default-src 'none'; frame-ancestors 'none'",
+		XContentTypeOptions:       "nosniff",
+		XFrameOptions:             "DENY",
+		ReferrerPolicy:            "no-referrer",
+		PermissionsPolicy:         "accelerometer=(), camera=(), geolocation=(), gyroscope=(), microphone=(), payment=(), usb=()",
+		CrossOriginOpenerPolicy:   "same-origin",
+		CrossOriginEmbedderPolicy: "require-corp",
+	}
+}
+
+// WebAppPreset returns headers for server-rendered web apps; inline scripts and styles need the request nonce.
+func WebAppPreset() *SecurityHeadersConfig {
+	return &SecurityHeadersConfig{
+		StrictTransportSecurity: "max-age=63072000; includeSubDomains",
+		ContentSecurityPolicy: "default-src 'self'; script-src 'self' 'nonce-" + NoncePlaceholder + "'; " +
+			"style-src 'self' 'nonce-" + NoncePlaceholder + "'; img-src 'self' data:; object-src 'none'; " +
+			"base-uri 'self'; form-action 'self'; frame-ancestors 'self'",
+		XContentTypeOptions:     "nosniff",
+		XFrameOptions:           "SAMEORIGIN",
+		ReferrerPolicy:          "strict-origin-when-cross-origin",
+		PermissionsPolicy:       "camera=(), geolocation=(), microphone=(), payment=(), usb=()",
+		CrossOriginOpenerPolicy: "same-origin",
+		GenerateNonce:           true,
+	}
+}
+
+// DocsPreset returns headers for API documentation UIs (Swagger UI, Redoc) which rely on inline styles and scripts.
+func DocsPreset() *SecurityHeadersConfig {
+	return &SecurityHeadersConfig{
+		StrictTransportSecurity: "max-age=63072000; includeSubDomains",
+		ContentSecurityPolicy: "default-src 'self'; script-src 'self' 'unsafe-inline'; style-src 'self' 'unsafe-inline'; " +
+			"img-src 'self' data: https:; font-src 'self' data:; object-src 'none'; frame-ancestors 'self'",
+		XContentTypeOptions:     "nosniff",
+		XFrameOptions:           "SAMEORIGIN",
+		ReferrerPolicy:          "strict-origin-when-cross-origin",
+		PermissionsPolicy:       "camera=(), geolocation=(), microphone=()",
+		CrossOriginOpenerPolicy: "same-origin",
+	}
+}
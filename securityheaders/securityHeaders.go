@@ -0,0 +1,198 @@
+// Package securityheaders sets browser security response headers (HSTS, CSP, framing, referrer, permissions, COOP/COEP).
+package securityheaders
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	ae "github.com/piyushkumar96/app-error"
+	cx "github.com/piyushkumar96/common-middlewares/context"
+	l "github.com/piyushkumar96/generic-logger"
+)
+
+// SecurityHeadersConfig configures the SecurityHeaders middleware. Empty fields are not emitted.
+type SecurityHeadersConfig struct {
+	StrictTransportSecurity string
+	// ContentSecurityPolicy is enforced by the browser; may contain NoncePlaceholder.
+	ContentSecurityPolicy string
+	// ContentSecurityPolicyReportOnly is only reported by the browser; may contain NoncePlaceholder.
+	ContentSecurityPolicyReportOnly string
+	XContentTypeOptions             string
+	XFrameOptions                   string
+	ReferrerPolicy                  string
+	PermissionsPolicy               string
+	CrossOriginOpenerPolicy         string
+	CrossOriginEmbedderPolicy       string
+	// CSPReportURI, when set, adds report-uri/report-to to both CSP headers and emits Reporting-Endpoints (see CSPReportHandler).
+	CSPReportURI string
+	// GenerateNonce creates a nonce per request, substitutes NoncePlaceholder and stores it for templates (see GetCSPNonce).
+	GenerateNonce bool
+	// RouteOverrides replaces the whole config for a route template (gin FullPath), e.g. DocsPreset() for "/docs/*any".
+	RouteOverrides map[string]*SecurityHeadersConfig
+}
+
+// SecurityHeaders returns a gin middleware that writes the configured security headers before the handler runs.
+// When GenerateNonce is off, sources using NoncePlaceholder are stripped from the policies (and logged once here).
+// It stops the service when config fails Validate.
+func SecurityHeaders(config *SecurityHeadersConfig) gin.HandlerFunc {
+	if err := config.Validate(); err != nil {
+		if l.Logger != nil {
+			l.Logger.Fatal(ErrInvalidConfig.Message, "code", ErrInvalidConfig.Code, "err", err.Error())
+		}
+		panic(err)
+	}
+	warnUnusedNonce("", config)
+	for route, override := range config.RouteOverrides {
+		warnUnusedNonce(route, override)
+	}
+	return func(gc *gin.Context) {
+		routeConfig := config
+		if override, ok := config.RouteOverrides[gc.FullPath()]; ok && override != nil {
+			routeConfig = override
+		}
+
+		nonce := ""
+		if routeConfig.GenerateNonce {
+			var err error
+			nonce, err = newNonce()
+			if err != nil {
+				ctx := cx.GetRequestContext(gc)
+				appErr := ae.GetAppErr(ctx, err, ErrNonceGeneration, http.StatusInternalServerError)
+				cx.RespondError(gc, appErr)
+				return
+			}
+			setCSPNonce(gc, nonce)
+		}
+
+		setHeader(gc, HeaderStrictTransportSecurity, routeConfig.StrictTransportSecurity)
+		setHeader(gc, HeaderContentSecurityPolicy, withReporting(withNonce(routeConfig.ContentSecurityPolicy, nonce), routeConfig.CSPReportURI))
+		setHeader(gc, HeaderContentSecurityPolicyReportOnly, withReporting(withNonce(routeConfig.ContentSecurityPolicyReportOnly, nonce), routeConfig.CSPReportURI))
+		if routeConfig.CSPReportURI != "" {
+			setHeader(gc, HeaderReportingEndpoints, ReportingEndpointName+`="`+routeConfig.CSPReportURI+`"`)
+		}
+		setHeader(gc, HeaderXContentTypeOptions, routeConfig.XContentTypeOptions)
+		setHeader(gc, HeaderXFrameOptions, routeConfig.XFrameOptions)
+		setHeader(gc, HeaderReferrerPolicy, routeConfig.ReferrerPolicy)
+		setHeader(gc, HeaderPermissionsPolicy, routeConfig.PermissionsPolicy)
+		setHeader(gc, HeaderCrossOriginOpenerPolicy, routeConfig.CrossOriginOpenerPolicy)
+		setHeader(gc, HeaderCrossOriginEmbedderPolicy, routeConfig.CrossOriginEmbedderPolicy)
+		gc.Next()
+	}
+}
+
+// GetCSPNonce returns the CSP nonce generated for this request, or empty string when nonces are disabled.
+// Pass it to templates as e.g. <script nonce="{{ .nonce }}">.
+func GetCSPNonce(gc *gin.Context) string {
+	return gc.GetString(cx.CSPNonceKey)
+}
+
+// GetCSPNonceFromContext returns the CSP nonce stored in the request context, or empty string.
+func GetCSPNonceFromContext(ctx context.Context) string {
+	if v, ok := ctx.Value(cx.CSPNonceKey).(string); ok {
+		return v
+	}
+	return ""
+}
+
+// setCSPNonce stores the nonce in gin and in the request context returned by GetRequestContext.
+func setCSPNonce(gc *gin.Context, nonce string) {
+	gc.Set(cx.CSPNonceKey, nonce)
+	ctx := context.WithValue(cx.GetRequestContext(gc), cx.CSPNonceKey, nonce)
+	cx.SetRequestContext(gc, ctx)
+}
+
+func newNonce() (string, error) {
+	b := make([]byte, nonceSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(b), nil
+}
+
+// withNonce substitutes NoncePlaceholder; without a nonce, every source containing the placeholder is dropped so
+// the browser never receives a literal 'nonce-{{nonce}}'.
+func withNonce(policy, nonce string) string {
+	if !strings.Contains(policy, NoncePlaceholder) {
+		return policy
+	}
+	if nonce != "" {
+		return strings.ReplaceAll(policy, NoncePlaceholder, nonce)
+	}
+	directives := strings.Split(policy, ";")
+	for i, directive := range directives {
+		sources := make([]string, 0)
+		for _, source := range strings.Fields(directive) {
+			if !strings.Contains(source, NoncePlaceholder) {
+				sources = append(sources, source)
+			}
+		}
+		directives[i] = strings.Join(sources, " ")
+	}
+	return strings.Join(directives, "; ")
+}
+
+// Validate rejects a config (or route override) whose policies have a directive made only of nonce sources while
+// GenerateNonce is off: stripping them would leave an empty directive, which blocks everything it governs.
+func (config *SecurityHeadersConfig) Validate() error {
+	errs := validateNonceSources("", config)
+	for route, override := range config.RouteOverrides {
+		errs = append(errs, validateNonceSources(route, override)...)
+	}
+	return errors.Join(errs...)
+}
+
+func validateNonceSources(route string, config *SecurityHeadersConfig) []error {
+	if config == nil || config.GenerateNonce {
+		return nil
+	}
+	var errs []error
+	for _, policy := range []string{config.ContentSecurityPolicy, config.ContentSecurityPolicyReportOnly} {
+		for _, directive := range strings.Split(policy, ";") {
+			fields := strings.Fields(directive)
+			if len(fields) < 2 {
+				continue
+			}
+			nonceOnly := true
+			for _, source := range fields[1:] {
+				nonceOnly = nonceOnly && strings.Contains(source, NoncePlaceholder)
+			}
+			if nonceOnly {
+				errs = append(errs, fmt.Errorf("%s: route %q: %s only has nonce sources but GenerateNonce is disabled",
+					ErrInvalidConfig.Code, route, fields[0]))
+			}
+		}
+	}
+	return errs
+}
+
+// warnUnusedNonce logs a config whose policies use NoncePlaceholder while GenerateNonce is off.
+func warnUnusedNonce(route string, config *SecurityHeadersConfig) {
+	if config == nil || config.GenerateNonce || l.Logger == nil {
+		return
+	}
+	if strings.Contains(config.ContentSecurityPolicy, NoncePlaceholder) ||
+		strings.Contains(config.ContentSecurityPolicyReportOnly, NoncePlaceholder) {
+		l.Logger.Warn("csp uses the nonce placeholder but GenerateNonce is disabled; nonce sources are stripped",
+			"placeholder", NoncePlaceholder, "route", route)
+	}
+}
+
+// withReporting appends both report-uri (legacy browsers) and report-to (Reporting API) to a non-empty policy.
+func withReporting(policy, reportURI string) string {
+	if policy == "" || reportURI == "" {
+		return policy
+	}
+	return strings.TrimRight(policy, "; ") + "; report-uri " + reportURI + "; report-to " + ReportingEndpointName
+}
+
+func setHeader(gc *gin.Context, key, value string) {
+	if value != "" {
+		gc.Header(key, value)
+	}
+}
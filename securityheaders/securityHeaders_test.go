@@ -0,0 +1,166 @@
+package securityheaders
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// scriptNonceRegex extracts the script-src nonce from a CSP header.
+var scriptNonceRegex = regexp.MustCompile(`script-src 'self' 'nonce-([A-Za-z0-9+/=]+)'`)
+
+// newTestRouter serves /page, which echoes the nonce seen by the handler, and /docs/*any.
+func newTestRouter(config *SecurityHeadersConfig) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(SecurityHeaders(config))
+	r.GET("/page", func(gc *gin.Context) {
+		gc.String(http.StatusOK, GetCSPNonce(gc)+"|"+GetCSPNonceFromContext(gc.Request.Context()))
+	})
+	r.GET("/docs/*any", func(gc *gin.Context) { gc.Status(http.StatusOK) })
+	return r
+}
+
+func serve(r *gin.Engine, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	return w
+}
+
+func TestSecurityHeadersAPIPreset(t *testing.T) {
+	preset := APIPreset()
+	w := serve(newTestRouter(preset), "/page")
+
+	want := map[string]string{
+		HeaderStrictTransportSecurity:         preset.StrictTransportSecurity,
+		HeaderContentSecurityPolicy:           preset.ContentSecurityPolicy,
+		HeaderContentSecurityPolicyReportOnly: "",
+		HeaderXContentTypeOptions:             "nosniff",
+		HeaderXFrameOptions:                   "DENY",
+		HeaderReferrerPolicy:                  "no-referrer",
+		HeaderPermissionsPolicy:               preset.PermissionsPolicy,
+		HeaderCrossOriginOpenerPolicy:         "same-origin",
+		HeaderCrossOriginEmbedderPolicy:       "require-corp",
+		HeaderReportingEndpoints:              "",
+	}
+	for header, value := range want {
+		if got := w.Header().Get(header); got != value {
+			t.Errorf("%s = %q, want %q", header, got, value)
+		}
+	}
+	if body := w.Body.String(); body != "|" {
+		t.Errorf("nonce without GenerateNonce = %q, want none", body)
+	}
+}
+
+func TestSecurityHeadersNonceInjection(t *testing.T) {
+	r := newTestRouter(WebAppPreset())
+
+	seen := make(map[string]bool)
+	for i := 0; i < 3; i++ {
+		w := serve(r, "/page")
+		csp := w.Header().Get(HeaderContentSecurityPolicy)
+		if strings.Contains(csp, NoncePlaceholder) {
+			t.Fatalf("placeholder left in CSP %q", csp)
+		}
+		match := scriptNonceRegex.FindStringSubmatch(csp)
+		if match == nil {
+			t.Fatalf("no script nonce in CSP %q", csp)
+		}
+		nonce := match[1]
+		if !strings.Contains(csp, "style-src 'self' 'nonce-"+nonce+"'") {
+			t.Errorf("style-src does not use the request nonce: %q", csp)
+		}
+		if body := w.Body.String(); body != nonce+"|"+nonce {
+			t.Errorf("handler nonces = %q, want %q from gin and the request context", body, nonce)
+		}
+		if seen[nonce] {
+			t.Errorf("nonce %q reused across requests", nonce)
+		}
+		seen[nonce] = true
+	}
+}
+
+func TestSecurityHeadersStripsNonceWhenDisabled(t *testing.T) {
+	config := WebAppPreset()
+	config.GenerateNonce = false
+	w := serve(newTestRouter(config), "/page")
+
+	csp := w.Header().Get(HeaderContentSecurityPolicy)
+	if strings.Contains(csp, "nonce") {
+		t.Errorf("CSP keeps nonce sources: %q", csp)
+	}
+	if !strings.Contains(csp, "script-src 'self';") || !strings.Contains(csp, "style-src 'self';") {
+		t.Errorf("CSP lost the remaining sources: %q", csp)
+	}
+}
+
+func TestSecurityHeadersRouteOverrideAndReporting(t *testing.T) {
+	config := APIPreset()
+	config.CSPReportURI = "/csp-report"
+	config.RouteOverrides = map[string]*SecurityHeadersConfig{"/docs/*any": DocsPreset()}
+	r := newTestRouter(config)
+
+	w := serve(r, "/page")
+	wantCSP := config.ContentSecurityPolicy + "; report-uri /csp-report; report-to " + ReportingEndpointName
+	if got := w.Header().Get(HeaderContentSecurityPolicy); got != wantCSP {
+		t.Errorf("CSP = %q, want %q", got, wantCSP)
+	}
+	if got, want := w.Header().Get(HeaderReportingEndpoints), ReportingEndpointName+`="/csp-report"`; got != want {
+		t.Errorf("%s = %q, want %q", HeaderReportingEndpoints, got, want)
+	}
+
+	w = serve(r, "/docs/index.html")
+	if got := w.Header().Get(HeaderContentSecurityPolicy); got != DocsPreset().ContentSecurityPolicy {
+		t.Errorf("override CSP = %q, want the docs preset", got)
+	}
+	if got := w.Header().Get(HeaderCrossOriginEmbedderPolicy); got != "" {
+		t.Errorf("override kept %s = %q", HeaderCrossOriginEmbedderPolicy, got)
+	}
+}
+
+func TestSecurityHeadersConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *SecurityHeadersConfig
+		wantErr bool
+	}{
+		{name: "presets", config: WebAppPreset()},
+		{name: "nonce with other sources", config: &SecurityHeadersConfig{
+			ContentSecurityPolicy: "script-src 'self' 'nonce-" + NoncePlaceholder + "'"}},
+		{name: "nonce only with GenerateNonce", config: &SecurityHeadersConfig{
+			ContentSecurityPolicy: "script-src 'nonce-" + NoncePlaceholder + "'", GenerateNonce: true}},
+		{name: "nonce only", wantErr: true, config: &SecurityHeadersConfig{
+			ContentSecurityPolicy: "default-src 'self'; script-src 'nonce-" + NoncePlaceholder + "'"}},
+		{name: "nonce only in report-only policy", wantErr: true, config: &SecurityHeadersConfig{
+			ContentSecurityPolicyReportOnly: "style-src 'nonce-" + NoncePlaceholder + "'"}},
+		{name: "nonce only in route override", wantErr: true, config: &SecurityHeadersConfig{
+			RouteOverrides: map[string]*SecurityHeadersConfig{
+				"/page": {ContentSecurityPolicy: "script-src 'nonce-" + NoncePlaceholder + "'"},
+			}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() = %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), ErrInvalidConfig.Code) {
+				t.Errorf("error %q misses code %s", err, ErrInvalidConfig.Code)
+			}
+		})
+	}
+}
+
+func TestSecurityHeadersRefusesNonceOnlyDirective(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("SecurityHeaders accepted a nonce-only directive without GenerateNonce")
+		}
+	}()
+	SecurityHeaders(&SecurityHeadersConfig{ContentSecurityPolicy: "script-src 'nonce-" + NoncePlaceholder + "'"})
+}
@@ -0,0 +1,229 @@
+package trace
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	cx "github.com/piyushkumar96/common-middlewares/context"
+)
+
+// BaggageMember is one W3C baggage list-member; Properties are kept verbatim (e.g. "ttl=30").
+type BaggageMember struct {
+	Key        string
+	Value      string
+	Properties []string
+}
+
+// Baggage is the W3C baggage of a request. It is stored as a pointer in the request context, so values set by
+// handlers are visible to everything holding that context (e.g. outbound clients). Safe for concurrent use.
+type Baggage struct {
+	mu      sync.RWMutex
+	members []BaggageMember
+}
+
+// ParseBaggage parses a baggage header, enforcing the W3C limits (8192 bytes, 180 members, 4096 bytes per member).
+// Invalid members are skipped; an error is returned only when the header as a whole exceeds the limits.
+func ParseBaggage(header string) (*Baggage, error) {
+	baggage := &Baggage{}
+	if header = strings.TrimSpace(header); header == "" {
+		return baggage, nil
+	}
+	if len(header) > maxBaggageBytes {
+		return baggage, fmt.Errorf("%s: %s: %d bytes exceeds %d", ErrInvalidBaggage.Code, ErrInvalidBaggage.Message, len(header), maxBaggageBytes)
+	}
+	rawMembers := strings.Split(header, ",")
+	if len(rawMembers) > maxBaggageMembers {
+		return baggage, fmt.Errorf("%s: %s: %d members exceeds %d", ErrInvalidBaggage.Code, ErrInvalidBaggage.Message, len(rawMembers), maxBaggageMembers)
+	}
+	for _, raw := range rawMembers {
+		if len(raw) > maxBaggageMemberBytes {
+			continue
+		}
+		parts := strings.Split(raw, ";")
+		key, value, found := strings.Cut(parts[0], "=")
+		key = strings.TrimSpace(key)
+		if !found || !isBaggageKey(key) {
+			continue
+		}
+		decoded, err := url.PathUnescape(strings.TrimSpace(value))
+		if err != nil {
+			continue
+		}
+		member := BaggageMember{Key: key, Value: decoded}
+		for _, property := range parts[1:] {
+			if property = strings.TrimSpace(property); isBaggageProperty(property) {
+				member.Properties = append(member.Properties, property)
+			}
+		}
+		baggage.set(member)
+	}
+	return baggage, nil
+}
+
+// Get returns the value for key.
+func (b *Baggage) Get(key string) (string, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, m := range b.members {
+		if m.Key == key {
+			return m.Value, true
+		}
+	}
+	return "", false
+}
+
+// Set adds or replaces key, keeping the encoded baggage within the W3C limits. Properties must already be encoded
+// ("key" or "key=value" with a percent-encoded value), so they cannot inject members.
+func (b *Baggage) Set(key, value string, properties ...string) error {
+	if !isBaggageKey(key) {
+		return fmt.Errorf("%s: %s: invalid key %q", ErrInvalidBaggage.Code, ErrInvalidBaggage.Message, key)
+	}
+	for _, property := range properties {
+		if !isBaggageProperty(property) {
+			return fmt.Errorf("%s: %s: invalid property %q of %q", ErrInvalidBaggage.Code, ErrInvalidBaggage.Message, property, key)
+		}
+	}
+	member := BaggageMember{Key: key, Value: value, Properties: properties}
+	if len(encodeBaggageMember(member)) > maxBaggageMemberBytes {
+		return fmt.Errorf("%s: %s: member %q exceeds %d bytes", ErrInvalidBaggage.Code, ErrInvalidBaggage.Message, key, maxBaggageMemberBytes)
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	previous := append([]BaggageMember(nil), b.members...)
+	b.set(member)
+	if len(b.members) > maxBaggageMembers || len(b.encode()) > maxBaggageBytes {
+		b.members = previous
+		return fmt.Errorf("%s: %s: adding %q exceeds the baggage limits", ErrInvalidBaggage.Code, ErrInvalidBaggage.Message, key)
+	}
+	return nil
+}
+
+// Delete removes key.
+func (b *Baggage) Delete(key string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i, m := range b.members {
+		if m.Key == key {
+			b.members = append(b.members[:i], b.members[i+1:]...)
+			return
+		}
+	}
+}
+
+// Members returns a copy of the members in header order.
+func (b *Baggage) Members() []BaggageMember {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return append([]BaggageMember(nil), b.members...)
+}
+
+// String encodes the baggage as a header value.
+func (b *Baggage) String() string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.encode()
+}
+
+// set adds or replaces a member; callers hold the lock (or own b exclusively).
+func (b *Baggage) set(member BaggageMember) {
+	for i, m := range b.members {
+		if m.Key == member.Key {
+			b.members[i] = member
+			return
+		}
+	}
+	b.members = append(b.members, member)
+}
+
+func (b *Baggage) encode() string {
+	encoded := make([]string, 0, len(b.members))
+	for _, m := range b.members {
+		encoded = append(encoded, encodeBaggageMember(m))
+	}
+	return strings.Join(encoded, ",")
+}
+
+func encodeBaggageMember(m BaggageMember) string {
+	return strings.Join(append([]string{m.Key + "=" + url.PathEscape(m.Value)}, m.Properties...), ";")
+}
+
+// isBaggageKey reports whether key is an RFC 7230 token.
+func isBaggageKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		if c <= ' ' || c >= 0x7f || strings.IndexByte(`"(),/:;<=>?@[\]{}`, c) >= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// isBaggageProperty reports whether property is a W3C property: a token key with an optional value made of
+// baggage-octets (printable ASCII except space, '"', ',', ';' and '\').
+func isBaggageProperty(property string) bool {
+	key, value, hasValue := strings.Cut(property, "=")
+	if !isBaggageKey(strings.TrimSpace(key)) {
+		return false
+	}
+	if !hasValue {
+		return true
+	}
+	value = strings.TrimSpace(value)
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if c <= ' ' || c >= 0x7f || strings.IndexByte(`",;\`, c) >= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// GetBaggage returns the request's baggage, or an empty Baggage when none is stored in ctx.
+func GetBaggage(ctx context.Context) *Baggage {
+	if baggage, ok := ctx.Value(cx.BaggageKey).(*Baggage); ok {
+		return baggage
+	}
+	return &Baggage{}
+}
+
+// GetBaggageValue returns the baggage value for key from the request context.
+func GetBaggageValue(ctx context.Context, key string) (string, bool) {
+	return GetBaggage(ctx).Get(key)
+}
+
+// SetBaggageValue sets key on the request's baggage so it is re-emitted on outbound calls (see InjectBaggage).
+// When ctx carries no baggage, a new context holding it is returned; otherwise ctx is returned unchanged.
+func SetBaggageValue(ctx context.Context, key, value string) (context.Context, error) {
+	baggage, ok := ctx.Value(cx.BaggageKey).(*Baggage)
+	if !ok {
+		baggage = &Baggage{}
+		ctx = context.WithValue(ctx, cx.BaggageKey, baggage)
+	}
+	return ctx, baggage.Set(key, value)
+}
+
+// InjectBaggage writes the request's baggage into header, e.g. before sending an outbound request.
+func InjectBaggage(ctx context.Context, header http.Header) {
+	if encoded := GetBaggage(ctx).String(); encoded != "" {
+		header.Set(HeaderBaggage, encoded)
+	}
+}
+
+// PromoteBaggage copies allowlisted baggage keys into ctxMeta.Baggage so they end up in logs (see cx.LogFields).
+func PromoteBaggage(ctxMeta *cx.CtxMeta, baggage *Baggage, keys []string) {
+	for _, key := range keys {
+		if value, ok := baggage.Get(key); ok {
+			if ctxMeta.Baggage == nil {
+				ctxMeta.Baggage = make(map[string]string, len(keys))
+			}
+			ctxMeta.Baggage[key] = value
+		}
+	}
+}
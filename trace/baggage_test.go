@@ -0,0 +1,64 @@
+package trace
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestBaggageSetProperties(t *testing.T) {
+	tests := []struct {
+		name       string
+		properties []string
+		wantErr    bool
+		want       string
+	}{
+		{name: "no properties", want: "tenant=acme%20corp"},
+		{name: "key and value properties", properties: []string{"ttl=30", "internal"}, want: "tenant=acme%20corp;ttl=30;internal"},
+		{name: "encoded property value", properties: []string{"note=a%2Cb"}, want: "tenant=acme%20corp;note=a%2Cb"},
+		{name: "comma injects a member", properties: []string{"ttl=30,admin=true"}, wantErr: true},
+		{name: "semicolon injects a property", properties: []string{"ttl=30;admin"}, wantErr: true},
+		{name: "space in value", properties: []string{"note=a b"}, wantErr: true},
+		{name: "invalid key", properties: []string{"bad key=1"}, wantErr: true},
+		{name: "empty property", properties: []string{""}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			baggage := &Baggage{}
+			err := baggage.Set("tenant", "acme corp", tt.properties...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Set() error = %v, want error %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if got := baggage.String(); got != "" {
+					t.Errorf("rejected member was stored: %q", got)
+				}
+				return
+			}
+			if got := baggage.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+			parsed, err := ParseBaggage(baggage.String())
+			if err != nil {
+				t.Fatalf("ParseBaggage: %v", err)
+			}
+			members := parsed.Members()
+			if len(members) != 1 || members[0].Value != "acme corp" || !slices.Equal(members[0].Properties, tt.properties) {
+				t.Errorf("round trip = %+v, want value %q with properties %v", members, "acme corp", tt.properties)
+			}
+		})
+	}
+}
+
+func TestParseBaggageDropsInvalidProperties(t *testing.T) {
+	baggage, err := ParseBaggage(`tenant=acme;ttl=30;bad"prop=1;flag, region=eu`)
+	if err != nil {
+		t.Fatalf("ParseBaggage: %v", err)
+	}
+	members := baggage.Members()
+	if len(members) != 2 {
+		t.Fatalf("members = %+v, want tenant and region", members)
+	}
+	if want := []string{"ttl=30", "flag"}; !slices.Equal(members[0].Properties, want) {
+		t.Errorf("tenant properties = %v, want %v", members[0].Properties, want)
+	}
+}
@@ -0,0 +1,85 @@
+package trace
+
+import (
+	"time"
+
+	cx "github.com/piyushkumar96/common-middlewares/context"
+)
+
+const (
+	// HeaderTraceParent and HeaderTraceState are the W3C Trace Context headers.
+	HeaderTraceParent = "traceparent"
+	HeaderTraceState  = "tracestate"
+
+	// HeaderB3 is the Zipkin B3 single header; the X-B3-* headers are the multi-header variant.
+	HeaderB3          = "b3"
+	HeaderB3TraceID   = "X-B3-TraceId"
+	HeaderB3SpanID    = "X-B3-SpanId"
+	HeaderB3Sampled   = "X-B3-Sampled"
+	HeaderB3Flags     = "X-B3-Flags"
+	HeaderUberTraceID = "uber-trace-id"
+
+	// HeaderBaggage is the W3C baggage header.
+	HeaderBaggage = "baggage"
+
+	// HeaderRequestID and HeaderDeploymentID are forwarded to downstream calls by Transport.
+	HeaderRequestID    = string(cx.HeaderRequestID)
+	HeaderDeploymentID = "x-deployment-id"
+
+	traceParentVersion         = 0x00
+	invalidVersion             = 0xff
+	traceIDHexLen              = 32
+	spanIDHexLen               = 16
+	flagSampled           byte = 0x01
+	maxTraceStateLen           = 512
+	maxBaggageBytes            = 8192
+	maxBaggageMembers          = 180
+	maxBaggageMemberBytes      = 4096
+)
+
+// clientSpanName prefixes the TraceMeta entry recorded for every outbound call.
+const clientSpanName = "http_client"
+
+const (
+	// messagePublishEventName and messageConsumeEventName are the TraceMeta events recorded by
+	// MessageAttributes and MessageContext; their span_id attributes link producer and consumer.
+	messagePublishEventName = "message_publish"
+	messageConsumeEventName = "message_consume"
+	defaultConsumerSpanName = "message consume"
+	tracedMessageVersion    = 1
+)
+
+const (
+	summaryLogMessage      = "request trace summary"
+	defaultSummaryMaxBytes = 16 * 1024
+)
+
+const (
+	defaultExportQueueSize     = 4096
+	defaultExportBatchSize     = 256
+	defaultExportFlushInterval = time.Second
+	defaultFileExporterMaxSize = 64 << 20
+	defaultFileExporterBackups = 3
+	defaultRingBufferCapacity  = 1000
+	defaultDebugListLimit      = 50
+)
+
+const (
+	// HeaderServerTiming carries backend timings to browser devtools.
+	HeaderServerTiming             = "Server-Timing"
+	serverTimingTotal              = "total"
+	defaultServerTimingBufferBytes = 1 << 20
+)
+
+// Reasons recorded on a SamplingDecision.
+const (
+	SamplingReasonAlways      = "always"
+	SamplingReasonParent      = "parent"
+	SamplingReasonDefaultRate = "default_rate"
+	SamplingReasonRouteRate   = "route_rate"
+	SamplingReasonRateLimited = "rate_limited"
+	SamplingReasonError       = "error"
+)
+
+// tracerName is the instrumentation scope name of the OpenTelemetry tracer.
+const tracerName = "github.com/piyushkumar96/common-middlewares/trace"
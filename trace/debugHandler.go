@@ -0,0 +1,43 @@
+package trace
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	ae "github.com/piyushkumar96/app-error"
+	cx "github.com/piyushkumar96/common-middlewares/context"
+)
+
+// DebugHandler lists the recent trace records of ring, newest first, with their events and errors.
+// Query parameters: request_id selects the records of one request, identifier and value select the requests
+// that carried a business identifier (e.g. identifier=order_id&value=42), limit caps the list (default 50).
+// Mount it on an internal route only; records can contain identifiers and error messages.
+func DebugHandler(ring *RingBufferExporter) gin.HandlerFunc {
+	return func(gc *gin.Context) {
+		if requestID := gc.Query("request_id"); requestID != "" {
+			records := ring.FindByRequestID(requestID)
+			if len(records) == 0 {
+				appErr := ae.GetAppErr(cx.GetRequestContext(gc), errors.New(ErrTraceNotFound.Message), ErrTraceNotFound, http.StatusNotFound)
+				cx.RespondError(gc, appErr)
+				return
+			}
+			cx.RespondSuccess(gc, http.StatusOK, map[string]interface{}{"traces": records}, nil)
+			return
+		}
+		if identifier := gc.Query("identifier"); identifier != "" {
+			value := gc.Query("value")
+			cx.RespondSuccess(gc, http.StatusOK, map[string]interface{}{
+				"request_ids": ring.RequestIDsByIdentifier(identifier, value),
+				"traces":      ring.FindByIdentifier(identifier, value),
+			}, nil)
+			return
+		}
+		limit, err := strconv.Atoi(gc.Query("limit"))
+		if err != nil || limit <= 0 {
+			limit = defaultDebugListLimit
+		}
+		cx.RespondSuccess(gc, http.StatusOK, map[string]interface{}{"traces": ring.Records(limit)}, nil)
+	}
+}
@@ -0,0 +1,55 @@
+package trace
+
+import (
+	ae "github.com/piyushkumar96/app-error"
+)
+
+var (
+	// ErrInvalidTraceParent is returned when the traceparent header does not follow the W3C Trace Context format.
+	ErrInvalidTraceParent = ae.GetCustomErr(
+		"ERR_TRACE_1001",
+		"invalid traceparent header",
+		false)
+
+	// ErrInvalidBaggage is returned when the baggage header or a baggage member violates the W3C limits or syntax.
+	ErrInvalidBaggage = ae.GetCustomErr(
+		"ERR_TRACE_1002",
+		"invalid baggage",
+		false)
+
+	// ErrOutboundRequestFailed is counted when an outbound call made through Transport fails without a response.
+	ErrOutboundRequestFailed = ae.GetCustomErr(
+		"ERR_TRACE_1003",
+		"outbound http request failed",
+		true)
+
+	// ErrOutboundServerError is counted when an outbound call made through Transport returns a 5xx status.
+	ErrOutboundServerError = ae.GetCustomErr(
+		"ERR_TRACE_1004",
+		"outbound http request returned a server error",
+		true)
+
+	// ErrTraceExportFailed is logged (and counted) when an exporter fails to write a batch.
+	ErrTraceExportFailed = ae.GetCustomErr(
+		"ERR_TRACE_1005",
+		"trace export failed",
+		true)
+
+	// ErrTraceExportDropped is counted when a trace record is dropped because the export queue is full.
+	ErrTraceExportDropped = ae.GetCustomErr(
+		"ERR_TRACE_1006",
+		"trace export queue full, record dropped",
+		true)
+
+	// ErrTraceNotFound is returned by the trace debug endpoint when no record matches the request ID.
+	ErrTraceNotFound = ae.GetCustomErr(
+		"ERR_TRACE_1007",
+		"trace not found",
+		false)
+
+	// ErrInvalidOptionsConfig is logged at setup when TraceConfig.Options cannot be applied as configured.
+	ErrInvalidOptionsConfig = ae.GetCustomErr(
+		"ERR_TRACE_1008",
+		"invalid options configuration, falling back to short_circuit",
+		false)
+)
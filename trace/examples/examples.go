@@ -23,7 +23,7 @@ func main() {
 		ctx := context.GetRequestContext(c)
 		meta := context.GetContextMeta(ctx)
 		reqID := meta.ReqID
-		c.JSON(http.StatusOK, gin.H{"message": "pong", "request_id": reqID})
+		c.JSON(http.StatusOK, gin.H{"message": "pong", "request_id": reqID, "trace_id": meta.TraceID, "span_id": meta.SpanID})
 	})
 
 	fmt.Println("Trace example: GET http://localhost:8080/ping")
@@ -0,0 +1,215 @@
+package trace
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	im "github.com/piyushkumar96/app-monitoring/interfaces"
+	l "github.com/piyushkumar96/generic-logger"
+)
+
+// Exporter writes finished trace records somewhere (a file, memory, ...). Export is only called from
+// the BatchExporter worker goroutine, so implementations do not need to be fast.
+type Exporter interface {
+	Export(records []*TraceSummary) error
+	Close() error
+}
+
+// MultiExporter fans every batch out to several exporters, e.g. a FileExporter and a RingBufferExporter behind one
+// BatchExporter. A failing exporter does not stop the others; their errors are joined.
+type MultiExporter []Exporter
+
+// Export passes records to every exporter.
+func (m MultiExporter) Export(records []*TraceSummary) error {
+	var errs []error
+	for _, exporter := range m {
+		if err := exporter.Export(records); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close closes every exporter.
+func (m MultiExporter) Close() error {
+	var errs []error
+	for _, exporter := range m {
+		if err := exporter.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// BatchExporterConfig configures NewBatchExporter; zero values use the defaults.
+type BatchExporterConfig struct {
+	// QueueSize bounds the records waiting for export; records beyond it are dropped and counted.
+	QueueSize int
+	// BatchSize is the maximum number of records passed to one Export call.
+	BatchSize int
+	// FlushInterval is the longest a queued record waits before being exported.
+	FlushInterval time.Duration
+	// MaxRecordBytes caps the JSON size of a record, like SummaryConfig.MaxRecordBytes.
+	MaxRecordBytes int
+	// AppMetrics, when set, counts export failures and dropped records.
+	AppMetrics im.AppMetricsInterface
+}
+
+// BatchExporter queues trace records and exports them in batches from a background goroutine.
+// Enqueueing never blocks: when the queue is full the record is dropped and Dropped is incremented.
+type BatchExporter struct {
+	exporter       Exporter
+	appMetrics     im.AppMetricsInterface
+	maxRecordBytes int
+	batchSize      int
+	flushInterval  time.Duration
+	queue          chan *TraceSummary
+	dropped        atomic.Uint64
+	mu             sync.RWMutex
+	closed         bool
+	done           chan struct{}
+	stop           chan struct{}
+	stopOnce       sync.Once
+	closeErr       error
+}
+
+// NewBatchExporter starts the export worker for exporter. Call Shutdown to flush and stop it.
+func NewBatchExporter(exporter Exporter, config *BatchExporterConfig) *BatchExporter {
+	if config == nil {
+		config = &BatchExporterConfig{}
+	}
+	b := &BatchExporter{
+		exporter:       exporter,
+		appMetrics:     config.AppMetrics,
+		maxRecordBytes: config.MaxRecordBytes,
+		batchSize:      config.BatchSize,
+		flushInterval:  config.FlushInterval,
+		done:           make(chan struct{}),
+		stop:           make(chan struct{}),
+	}
+	queueSize := config.QueueSize
+	if queueSize <= 0 {
+		queueSize = defaultExportQueueSize
+	}
+	if b.batchSize <= 0 {
+		b.batchSize = defaultExportBatchSize
+	}
+	if b.flushInterval <= 0 {
+		b.flushInterval = defaultExportFlushInterval
+	}
+	b.queue = make(chan *TraceSummary, queueSize)
+	go b.run()
+	return b
+}
+
+// Enqueue queues record for export; it returns false when the record was dropped.
+func (b *BatchExporter) Enqueue(record *TraceSummary) bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		b.drop()
+		return false
+	}
+	select {
+	case b.queue <- record:
+		return true
+	default:
+		b.drop()
+		return false
+	}
+}
+
+// Dropped returns the number of records dropped because the queue was full or the exporter was shut down.
+func (b *BatchExporter) Dropped() uint64 {
+	return b.dropped.Load()
+}
+
+// Shutdown stops accepting records and waits for the worker to export the queued ones and close the exporter,
+// returning the Close error. When ctx is done first, the worker is told to drop (and count) the records not yet
+// exported and to close the exporter once its current Export returns; Shutdown returns ctx.Err() without waiting.
+func (b *BatchExporter) Shutdown(ctx context.Context) error {
+	b.mu.Lock()
+	if !b.closed {
+		b.closed = true
+		close(b.queue)
+	}
+	b.mu.Unlock()
+	select {
+	case <-b.done:
+		return b.closeErr
+	case <-ctx.Done():
+		b.stopOnce.Do(func() {
+			close(b.stop)
+		})
+		return ctx.Err()
+	}
+}
+
+func (b *BatchExporter) drop() {
+	b.dropped.Add(1)
+	if b.appMetrics != nil {
+		b.appMetrics.LogMetrics([]string{ErrTraceExportDropped.Code})
+	}
+}
+
+// run exports batches until the queue is closed or stop is signalled; it owns the exporter, so Close is only
+// called here, after the last Export returned.
+func (b *BatchExporter) run() {
+	defer close(b.done)
+	defer func() {
+		b.closeErr = b.exporter.Close()
+	}()
+	ticker := time.NewTicker(b.flushInterval)
+	defer ticker.Stop()
+	batch := make([]*TraceSummary, 0, b.batchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := b.exporter.Export(batch); err != nil {
+			if l.Logger != nil {
+				l.Logger.Error(ErrTraceExportFailed.Message, "code", ErrTraceExportFailed.Code, "records", len(batch), "err", err.Error())
+			}
+			if b.appMetrics != nil {
+				b.appMetrics.LogMetrics([]string{ErrTraceExportFailed.Code})
+			}
+		}
+		batch = make([]*TraceSummary, 0, b.batchSize)
+	}
+	abandon := func() {
+		for range batch {
+			b.drop()
+		}
+		for range b.queue {
+			b.drop()
+		}
+	}
+	for {
+		// a stop (Shutdown timed out) wins over queued records
+		select {
+		case <-b.stop:
+			abandon()
+			return
+		default:
+		}
+		select {
+		case record, ok := <-b.queue:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, record)
+			if len(batch) >= b.batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-b.stop:
+			abandon()
+			return
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package trace
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingExporter records the size of every batch and whether Close ran; release, when set, blocks Export.
+type recordingExporter struct {
+	mu      sync.Mutex
+	batches []int
+	closed  bool
+	started chan struct{}
+	release chan struct{}
+	err     error
+}
+
+func (e *recordingExporter) Export(records []*TraceSummary) error {
+	if e.started != nil {
+		e.started <- struct{}{}
+	}
+	if e.release != nil {
+		<-e.release
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.closed {
+		return errors.New("export after close")
+	}
+	e.batches = append(e.batches, len(records))
+	return e.err
+}
+
+func (e *recordingExporter) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.closed = true
+	return nil
+}
+
+func (e *recordingExporter) state() ([]int, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return slices.Clone(e.batches), e.closed
+}
+
+func testRecords(requestIDs ...string) []*TraceSummary {
+	records := make([]*TraceSummary, 0, len(requestIDs))
+	for _, requestID := range requestIDs {
+		records = append(records, &TraceSummary{RequestID: requestID, Method: "GET", Route: "/items"})
+	}
+	return records
+}
+
+func TestBatchExporterBatches(t *testing.T) {
+	exporter := &recordingExporter{}
+	batch := NewBatchExporter(exporter, &BatchExporterConfig{BatchSize: 2, FlushInterval: time.Hour})
+	for _, record := range testRecords("a", "b", "c", "d", "e") {
+		if !batch.Enqueue(record) {
+			t.Fatalf("record %s dropped", record.RequestID)
+		}
+	}
+
+	if err := batch.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	batches, closed := exporter.state()
+	if want := []int{2, 2, 1}; !slices.Equal(batches, want) {
+		t.Errorf("batches = %v, want %v", batches, want)
+	}
+	if !closed {
+		t.Error("exporter not closed")
+	}
+	if batch.Enqueue(testRecords("f")[0]) || batch.Dropped() != 1 {
+		t.Errorf("enqueue after Shutdown accepted, dropped = %d", batch.Dropped())
+	}
+}
+
+func TestBatchExporterFlushInterval(t *testing.T) {
+	exporter := &recordingExporter{started: make(chan struct{}, 1)}
+	batch := NewBatchExporter(exporter, &BatchExporterConfig{BatchSize: 10, FlushInterval: 10 * time.Millisecond})
+	batch.Enqueue(testRecords("a")[0])
+
+	select {
+	case <-exporter.started:
+	case <-time.After(time.Second):
+		t.Fatal("partial batch not flushed after FlushInterval")
+	}
+	if err := batch.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+}
+
+func TestBatchExporterShutdownTimeout(t *testing.T) {
+	exporter := &recordingExporter{started: make(chan struct{}, 1), release: make(chan struct{})}
+	batch := NewBatchExporter(exporter, &BatchExporterConfig{BatchSize: 1, FlushInterval: time.Hour})
+	for _, record := range testRecords("a", "b", "c") {
+		batch.Enqueue(record)
+	}
+	<-exporter.started // the worker is blocked exporting "a"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := batch.Shutdown(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Shutdown = %v, want context.Canceled", err)
+	}
+	if _, closed := exporter.state(); closed {
+		t.Fatal("exporter closed while an Export was in flight")
+	}
+
+	close(exporter.release)
+	<-batch.done
+	batches, closed := exporter.state()
+	if !slices.Equal(batches, []int{1}) || !closed {
+		t.Errorf("batches = %v closed = %v, want the in-flight batch exported before Close", batches, closed)
+	}
+	if got := batch.Dropped(); got != 2 {
+		t.Errorf("dropped = %d, want 2", got)
+	}
+}
+
+func TestMultiExporter(t *testing.T) {
+	failing := &recordingExporter{err: errors.New("disk full")}
+	ring := NewRingBufferExporter(10)
+	multi := MultiExporter{failing, ring}
+
+	if err := multi.Export(testRecords("a", "b")); err == nil {
+		t.Error("Export did not report the failing exporter")
+	}
+	if got := len(ring.Records(0)); got != 2 {
+		t.Errorf("ring records = %d, want 2 despite the failing exporter", got)
+	}
+	if err := multi.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if _, closed := failing.state(); !closed {
+		t.Error("exporter not closed")
+	}
+}
+
+func TestFileExporterRotates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traces.jsonl")
+	line, err := json.Marshal(testRecords("req-00")[0])
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	exporter, err := NewFileExporter(&FileExporterConfig{Path: path, MaxBytes: int64(2 * (len(line) + 1)), MaxBackups: 2})
+	if err != nil {
+		t.Fatalf("NewFileExporter: %v", err)
+	}
+	for i := 0; i < 7; i++ {
+		if err := exporter.Export(testRecords(fmt.Sprintf("req-%02d", i))); err != nil {
+			t.Fatalf("Export %d: %v", i, err)
+		}
+	}
+	if err := exporter.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	// two records per file: req-06 is current, req-04/05 in .1, req-02/03 in .2 and req-00/01 were rotated out
+	want := map[string][]string{
+		path:        {"req-06"},
+		path + ".1": {"req-04", "req-05"},
+		path + ".2": {"req-02", "req-03"},
+	}
+	for file, wantIDs := range want {
+		if got := readRequestIDs(t, file); !slices.Equal(got, wantIDs) {
+			t.Errorf("%s = %v, want %v", filepath.Base(file), got, wantIDs)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("backup beyond MaxBackups kept: %v", err)
+	}
+}
+
+func readRequestIDs(t *testing.T, path string) []string {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open %s: %v", path, err)
+	}
+	defer file.Close()
+	var requestIDs []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record TraceSummary
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("%s: invalid JSON line %q: %v", path, scanner.Text(), err)
+		}
+		requestIDs = append(requestIDs, record.RequestID)
+	}
+	return requestIDs
+}
+
+func TestRingBufferExporterLookup(t *testing.T) {
+	ring := NewRingBufferExporter(3)
+	records := testRecords("a", "b", "a", "c")
+	records[2].IdentifierMappings = map[string]interface{}{"order_id": 42}
+	if err := ring.Export(records); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+
+	if got := requestIDsOf(ring.Records(0)); !slices.Equal(got, []string{"c", "a", "b"}) {
+		t.Errorf("Records = %v, want newest first with the oldest overwritten", got)
+	}
+	if got := ring.FindByRequestID("a"); len(got) != 1 || got[0] != records[2] {
+		t.Errorf("FindByRequestID(a) = %v, want only the newer record", requestIDsOf(got))
+	}
+	if got := ring.FindByRequestID("missing"); len(got) != 0 {
+		t.Errorf("FindByRequestID(missing) = %v", requestIDsOf(got))
+	}
+	if got := ring.RequestIDsByIdentifier("order_id", "42"); !slices.Equal(got, []string{"a"}) {
+		t.Errorf("RequestIDsByIdentifier = %v, want [a]", got)
+	}
+}
+
+func requestIDsOf(records []*TraceSummary) []string {
+	requestIDs := make([]string, 0, len(records))
+	for _, record := range records {
+		requestIDs = append(requestIDs, record.RequestID)
+	}
+	return requestIDs
+}
@@ -0,0 +1,108 @@
+package trace
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// FileExporterConfig configures NewFileExporter.
+type FileExporterConfig struct {
+	// Path of the JSON-lines file; rotated files get a numeric suffix (Path.1 is the newest).
+	Path string
+	// MaxBytes rotates the file before a write would exceed it (0 uses the default, 64 MiB).
+	MaxBytes int64
+	// MaxBackups is the number of rotated files kept (0 uses the default, 3).
+	MaxBackups int
+}
+
+// FileExporter writes one JSON trace record per line and rotates the file by size.
+type FileExporter struct {
+	mu         sync.Mutex
+	path       string
+	maxBytes   int64
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+// NewFileExporter opens (or creates) config.Path for appending.
+func NewFileExporter(config *FileExporterConfig) (*FileExporter, error) {
+	e := &FileExporter{path: config.Path, maxBytes: config.MaxBytes, maxBackups: config.MaxBackups}
+	if e.maxBytes <= 0 {
+		e.maxBytes = defaultFileExporterMaxSize
+	}
+	if e.maxBackups <= 0 {
+		e.maxBackups = defaultFileExporterBackups
+	}
+	if err := e.open(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Export appends records to the file, rotating it when it grows past MaxBytes.
+func (e *FileExporter) Export(records []*TraceSummary) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, record := range records {
+		line, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+		line = append(line, '\n')
+		if e.size > 0 && e.size+int64(len(line)) > e.maxBytes {
+			if err := e.rotate(); err != nil {
+				return err
+			}
+		}
+		n, err := e.file.Write(line)
+		e.size += int64(n)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close closes the current file.
+func (e *FileExporter) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.file.Close()
+}
+
+func (e *FileExporter) open() error {
+	file, err := os.OpenFile(e.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	e.file, e.size = file, info.Size()
+	return nil
+}
+
+// rotate shifts Path.N-1 to Path.N (dropping the oldest), moves Path to Path.1 and reopens Path.
+func (e *FileExporter) rotate() error {
+	if err := e.file.Close(); err != nil {
+		return err
+	}
+	for i := e.maxBackups - 1; i >= 1; i-- {
+		if err := os.Rename(e.backupPath(i), e.backupPath(i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if err := os.Rename(e.path, e.backupPath(1)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return e.open()
+}
+
+func (e *FileExporter) backupPath(index int) string {
+	return fmt.Sprintf("%s.%d", e.path, index)
+}
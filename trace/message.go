@@ -0,0 +1,162 @@
+package trace
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	cx "github.com/piyushkumar96/common-middlewares/context"
+	l "github.com/piyushkumar96/generic-logger"
+	"go.opentelemetry.io/otel"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// MessageContextConfig configures MessageContext.
+type MessageContextConfig struct {
+	// RequestID validates the producer's request ID and generates one when it is missing; nil uses the
+	// process-wide configuration (see context.SetRequestIDConfig).
+	RequestID *cx.RequestIDConfig
+	// EnableOTelSpans starts an OpenTelemetry consumer span, child of and linked to the producer span.
+	EnableOTelSpans bool
+	// TracerProvider is used for the consumer spans; nil falls back to otel.GetTracerProvider().
+	TracerProvider oteltrace.TracerProvider
+	// SpanName names the consumer span; empty uses "message consume".
+	SpanName string
+	// BaggagePromotedKeys are W3C baggage keys copied into CtxMeta.Baggage, as in TraceConfig.
+	BaggagePromotedKeys []string
+}
+
+// TracedMessage is the envelope written by WrapMessage, for brokers without native message attributes.
+type TracedMessage struct {
+	Version    int               `json:"traced_message_version"`
+	Attributes map[string]string `json:"attributes"`
+	Data       []byte            `json:"data"`
+}
+
+// MessageAttributes serializes the trace context of ctx (with a new producer span ID), request ID, user and
+// deployment IDs and baggage into message attributes keyed by the lower-cased HTTP header names. The publish is
+// recorded in TraceMeta with the producer span ID.
+func MessageAttributes(ctx context.Context) map[string]string {
+	ctxMeta := cx.GetContextMeta(ctx)
+	header := http.Header{}
+	for _, h := range DefaultOutboundHeaders() {
+		if value := h.Value(ctxMeta); value != "" {
+			header.Set(h.Name, value)
+		}
+	}
+	InjectBaggage(ctx, header)
+	if ctxMeta.TraceID != "" {
+		producerSpanID := NewSpanID()
+		W3CPropagator{}.Inject(SpanContext{TraceID: ctxMeta.TraceID, SpanID: producerSpanID, Sampled: ctxMeta.Sampled, TraceState: ctxMeta.TraceState}, header)
+		cx.RecordEvent(ctx, messagePublishEventName, cx.SeverityInfo, map[string]interface{}{"span_id": producerSpanID})
+	}
+
+	attributes := make(map[string]string, len(header))
+	for name := range header {
+		attributes[strings.ToLower(name)] = header.Get(name)
+	}
+	return attributes
+}
+
+// WrapMessage wraps payload and the MessageAttributes of ctx into a JSON TracedMessage.
+func WrapMessage(ctx context.Context, payload []byte) ([]byte, error) {
+	return json.Marshal(&TracedMessage{Version: tracedMessageVersion, Attributes: MessageAttributes(ctx), Data: payload})
+}
+
+// UnwrapMessage returns the payload and attributes of a message written by WrapMessage. Any other message is
+// returned unchanged with nil attributes, so consumers can be migrated before producers.
+func UnwrapMessage(message []byte) ([]byte, map[string]string) {
+	var traced TracedMessage
+	if err := json.Unmarshal(message, &traced); err != nil || traced.Version != tracedMessageVersion {
+		return message, nil
+	}
+	return traced.Data, traced.Attributes
+}
+
+// MessageContext rebuilds a request context from message attributes for the consumer: the producer's request,
+// user and deployment IDs, its trace (with a fresh span ID for this hop and the producer span as parent), baggage
+// and a new TraceMeta whose first event links to the producer span. Consumer logs therefore correlate with the
+// originating HTTP request. The returned span is a no-op unless EnableOTelSpans is set; always End it.
+func MessageContext(parent context.Context, attributes map[string]string, config *MessageContextConfig) (context.Context, oteltrace.Span) {
+	if config == nil {
+		config = &MessageContextConfig{}
+	}
+	header := http.Header{}
+	for name, value := range attributes {
+		header.Set(name, value)
+	}
+
+	/** initialize context meta */
+	requestIDConfig := config.RequestID
+	if requestIDConfig == nil {
+		requestIDConfig = cx.GetRequestIDConfig()
+	}
+	reqID := header.Get(HeaderRequestID)
+	if !requestIDConfig.IsValid(reqID) {
+		reqID = requestIDConfig.NewRequestID()
+	}
+	ctxMeta := &cx.CtxMeta{
+		ReqID:        reqID,
+		UserID:       header.Get(string(cx.HeaderUserIDKey)),
+		DeploymentID: header.Get(HeaderDeploymentID),
+		Time:         time.Now(),
+	}
+	inbound, ok := ExtractSpanContext(header, []Propagator{W3CPropagator{}})
+	ApplySpanContext(ctxMeta, inbound, ok)
+	ctx := context.WithValue(parent, cx.CtxMetaKey, ctxMeta)
+	ctx = context.WithValue(ctx, cx.ReqIDKey, ctxMeta.ReqID)
+
+	/** initialize baggage */
+	baggage, err := ParseBaggage(header.Get(HeaderBaggage))
+	if err != nil && l.Logger != nil {
+		l.Logger.Debug(ErrInvalidBaggage.Message, "code", ErrInvalidBaggage.Code, "err", err.Error())
+	}
+	PromoteBaggage(ctxMeta, baggage, config.BaggagePromotedKeys)
+	ctx = context.WithValue(ctx, cx.BaggageKey, baggage)
+
+	/** initialize trace meta */
+	key, traceMeta := cx.InitTraceMeta()
+	ctx = context.WithValue(ctx, key, traceMeta)
+
+	/** start the OpenTelemetry consumer span */
+	span := oteltrace.SpanFromContext(ctx)
+	if config.EnableOTelSpans {
+		ctx, span = startConsumerSpan(ctx, config, ctxMeta)
+	}
+	if ok {
+		cx.RecordEvent(ctx, messageConsumeEventName, cx.SeverityInfo, map[string]interface{}{
+			"span_id":          ctxMeta.SpanID,
+			"producer_span_id": ctxMeta.ParentSpanID,
+		})
+	}
+	return ctx, span
+}
+
+// startConsumerSpan starts a consumer span, child of and linked to the producer span recorded in ctxMeta,
+// then aligns ctxMeta with it like startServerSpan does.
+func startConsumerSpan(ctx context.Context, config *MessageContextConfig, ctxMeta *cx.CtxMeta) (context.Context, oteltrace.Span) {
+	provider := config.TracerProvider
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+	name := config.SpanName
+	if name == "" {
+		name = defaultConsumerSpanName
+	}
+	opts := []oteltrace.SpanStartOption{oteltrace.WithSpanKind(oteltrace.SpanKindConsumer)}
+	if producer, ok := remoteParent(ctxMeta); ok {
+		ctx = oteltrace.ContextWithRemoteSpanContext(ctx, producer)
+		opts = append(opts, oteltrace.WithLinks(oteltrace.Link{SpanContext: producer}))
+	}
+	ctx, span := provider.Tracer(tracerName).Start(ctx, name, opts...)
+
+	if sc := span.SpanContext(); sc.IsValid() {
+		ctxMeta.TraceID = sc.TraceID().String()
+		ctxMeta.SpanID = sc.SpanID().String()
+		ctxMeta.Sampled = sc.IsSampled()
+		ctxMeta.TraceParent = (&TraceParent{TraceID: ctxMeta.TraceID, ParentID: ctxMeta.SpanID, Flags: byte(sc.TraceFlags())}).String()
+	}
+	return ctx, span
+}
@@ -0,0 +1,26 @@
+package trace
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestUnwrapMessageFallsBack(t *testing.T) {
+	tests := []struct {
+		name    string
+		message []byte
+	}{
+		{name: "plain text", message: []byte("order created")},
+		{name: "json payload", message: []byte(`{"order_id":42,"attributes":{"traceparent":"x"}}`)},
+		{name: "other envelope version", message: []byte(`{"traced_message_version":99,"attributes":{},"data":"eA=="}`)},
+		{name: "empty", message: []byte{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload, attributes := UnwrapMessage(tt.message)
+			if !bytes.Equal(payload, tt.message) || attributes != nil {
+				t.Errorf("UnwrapMessage = %q, %v; want the message unchanged and nil attributes", payload, attributes)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package trace
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	l "github.com/piyushkumar96/generic-logger"
+)
+
+// OptionsMode decides how TraceWithConfig answers OPTIONS requests.
+type OptionsMode string
+
+const (
+	// OptionsShortCircuit answers every OPTIONS request with 204 without running the remaining handlers (default).
+	OptionsShortCircuit OptionsMode = "short_circuit"
+	// OptionsPassThrough runs the remaining handlers, so CORS middlewares and OPTIONS routes see the request.
+	OptionsPassThrough OptionsMode = "pass_through"
+	// OptionsDelegate hands the request to TraceConfig.OptionsHandler, e.g. cors.CORSWithPolicy(policy).
+	OptionsDelegate OptionsMode = "delegate"
+)
+
+// serveOptions runs an OPTIONS request according to config.Options; the request context is already set up.
+func serveOptions(config *TraceConfig, gc *gin.Context) {
+	switch {
+	case config.Options == OptionsPassThrough:
+		gc.Next()
+	case config.Options == OptionsDelegate && config.OptionsHandler != nil:
+		config.OptionsHandler(gc)
+		if !gc.IsAborted() {
+			gc.Next()
+		}
+	default:
+		gc.AbortWithStatus(http.StatusNoContent)
+	}
+}
+
+// checkOptionsConfig logs, once at setup, an OPTIONS policy that serveOptions would silently short-circuit.
+func checkOptionsConfig(config *TraceConfig) {
+	if l.Logger == nil {
+		return
+	}
+	switch config.Options {
+	case "", OptionsShortCircuit, OptionsPassThrough:
+	case OptionsDelegate:
+		if config.OptionsHandler == nil {
+			l.Logger.Error(ErrInvalidOptionsConfig.Message, "code", ErrInvalidOptionsConfig.Code,
+				"options", config.Options, "reason", "OptionsHandler is nil")
+		}
+	default:
+		l.Logger.Error(ErrInvalidOptionsConfig.Message, "code", ErrInvalidOptionsConfig.Code,
+			"options", config.Options, "reason", "unknown options mode")
+	}
+}
@@ -0,0 +1,149 @@
+package trace
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	cx "github.com/piyushkumar96/common-middlewares/context"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// newServerTracer returns the tracer for server spans, or nil when OTel spans are disabled.
+func newServerTracer(config *TraceConfig) oteltrace.Tracer {
+	if !config.EnableOTelSpans {
+		return nil
+	}
+	provider := config.TracerProvider
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+	return provider.Tracer(tracerName)
+}
+
+// startServerSpan starts a server span whose parent is the inbound traceparent already parsed into ctxMeta,
+// then aligns ctxMeta with the span so logs and exported spans share the same IDs. With a head sampling
+// decision the decision wins: unsampled requests get a non-recording span carrying ctxMeta's IDs (sampled=0),
+// and ctxMeta.Sampled is never overwritten by the tracer provider's own sampler.
+func startServerSpan(ctx context.Context, tracer oteltrace.Tracer, gc *gin.Context, ctxMeta *cx.CtxMeta, decision *SamplingDecision) (context.Context, oteltrace.Span) {
+	if decision != nil && !decision.Sampled() {
+		if sc, ok := localSpanContext(ctxMeta); ok {
+			ctx = oteltrace.ContextWithSpanContext(ctx, sc)
+		}
+		return ctx, oteltrace.SpanFromContext(ctx)
+	}
+	if parent, ok := remoteParent(ctxMeta); ok {
+		ctx = oteltrace.ContextWithRemoteSpanContext(ctx, parent)
+	}
+
+	route := gc.FullPath()
+	spanName := gc.Request.Method
+	if route != "" {
+		spanName += " " + route
+	}
+	attrs := []attribute.KeyValue{
+		semconv.HTTPRequestMethodKey.String(gc.Request.Method),
+		semconv.URLPath(gc.Request.URL.Path),
+		semconv.URLScheme(requestScheme(gc.Request)),
+		semconv.ServerAddress(gc.Request.Host),
+		semconv.ClientAddress(gc.ClientIP()),
+		semconv.UserAgentOriginal(gc.Request.UserAgent()),
+	}
+	if route != "" {
+		attrs = append(attrs, semconv.HTTPRoute(route))
+	}
+	ctx, span := tracer.Start(ctx, spanName, oteltrace.WithSpanKind(oteltrace.SpanKindServer), oteltrace.WithAttributes(attrs...))
+
+	if sc := span.SpanContext(); sc.IsValid() {
+		ctxMeta.TraceID = sc.TraceID().String()
+		ctxMeta.SpanID = sc.SpanID().String()
+		if decision == nil {
+			ctxMeta.Sampled = sc.IsSampled()
+		}
+		tp := &TraceParent{TraceID: ctxMeta.TraceID, ParentID: ctxMeta.SpanID}
+		if ctxMeta.Sampled {
+			tp.Flags = flagSampled
+		}
+		ctxMeta.TraceParent = tp.String()
+	}
+	return ctx, span
+}
+
+// endServerSpan records the response status and handler errors (gc.Errors) and ends the span.
+// Per the HTTP semantic conventions only 5xx marks a server span as failed. It must be deferred: a handler
+// panic is recorded as a 500 with the panic value and then re-raised for the recovery middleware.
+func endServerSpan(span oteltrace.Span, gc *gin.Context) {
+	recovered := recover()
+	status := gc.Writer.Status()
+	if recovered != nil {
+		status = http.StatusInternalServerError
+	}
+	span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+	for _, ginErr := range gc.Errors {
+		span.RecordError(ginErr.Err)
+	}
+	if recovered != nil {
+		span.RecordError(fmt.Errorf("panic: %v", recovered), oteltrace.WithStackTrace(true))
+		span.SetStatus(codes.Error, fmt.Sprint(recovered))
+		span.End()
+		panic(recovered)
+	}
+	if status >= http.StatusInternalServerError {
+		description := http.StatusText(status)
+		if last := gc.Errors.Last(); last != nil {
+			description = last.Error()
+		}
+		span.SetStatus(codes.Error, description)
+	}
+	span.End()
+}
+
+func remoteParent(ctxMeta *cx.CtxMeta) (oteltrace.SpanContext, bool) {
+	if ctxMeta.ParentSpanID == "" {
+		return oteltrace.SpanContext{}, false
+	}
+	traceID, err := oteltrace.TraceIDFromHex(ctxMeta.TraceID)
+	if err != nil {
+		return oteltrace.SpanContext{}, false
+	}
+	spanID, err := oteltrace.SpanIDFromHex(ctxMeta.ParentSpanID)
+	if err != nil {
+		return oteltrace.SpanContext{}, false
+	}
+	var flags oteltrace.TraceFlags
+	if ctxMeta.Sampled {
+		flags = oteltrace.FlagsSampled
+	}
+	sc := oteltrace.NewSpanContext(oteltrace.SpanContextConfig{TraceID: traceID, SpanID: spanID, TraceFlags: flags, Remote: true})
+	return sc, sc.IsValid()
+}
+
+// localSpanContext is this hop's span context as recorded in ctxMeta, for requests without a recording span.
+func localSpanContext(ctxMeta *cx.CtxMeta) (oteltrace.SpanContext, bool) {
+	traceID, err := oteltrace.TraceIDFromHex(ctxMeta.TraceID)
+	if err != nil {
+		return oteltrace.SpanContext{}, false
+	}
+	spanID, err := oteltrace.SpanIDFromHex(ctxMeta.SpanID)
+	if err != nil {
+		return oteltrace.SpanContext{}, false
+	}
+	var flags oteltrace.TraceFlags
+	if ctxMeta.Sampled {
+		flags = oteltrace.FlagsSampled
+	}
+	sc := oteltrace.NewSpanContext(oteltrace.SpanContextConfig{TraceID: traceID, SpanID: spanID, TraceFlags: flags})
+	return sc, sc.IsValid()
+}
+
+func requestScheme(req *http.Request) string {
+	if req.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
@@ -0,0 +1,215 @@
+package trace
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	l "github.com/piyushkumar96/generic-logger"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+const (
+	testTraceID      = "4bf92f3577b34da6a3ce929d0e0e4736"
+	testParentSpanID = "00f067aa0ba902b7"
+)
+
+func newSpanTestRouter(t *testing.T, config *TraceConfig, opts ...sdktrace.TracerProviderOption) (*gin.Engine, *tracetest.SpanRecorder) {
+	t.Helper()
+	l.Init()
+	gin.SetMode(gin.TestMode)
+	recorder := tracetest.NewSpanRecorder()
+	config.EnableOTelSpans = true
+	config.TracerProvider = sdktrace.NewTracerProvider(append(opts, sdktrace.WithSpanProcessor(recorder))...)
+
+	r := gin.New()
+	r.Use(gin.CustomRecovery(func(gc *gin.Context, _ any) {
+		gc.AbortWithStatus(http.StatusInternalServerError)
+	}))
+	r.Use(TraceWithConfig(config))
+	r.GET("/items/:id", func(gc *gin.Context) { gc.Status(http.StatusOK) })
+	r.GET("/missing", func(gc *gin.Context) { gc.Status(http.StatusNotFound) })
+	r.GET("/fail", func(gc *gin.Context) { gc.Status(http.StatusServiceUnavailable) })
+	r.GET("/panic", func(gc *gin.Context) { panic("boom") })
+	r.GET("/span-context", func(gc *gin.Context) {
+		gc.String(http.StatusOK, oteltrace.SpanContextFromContext(gc.Request.Context()).TraceID().String())
+	})
+	return r, recorder
+}
+
+func serve(r *gin.Engine, path string, header http.Header) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func endedSpan(t *testing.T, recorder *tracetest.SpanRecorder) sdktrace.ReadOnlySpan {
+	t.Helper()
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("ended spans = %d, want 1", len(spans))
+	}
+	return spans[0]
+}
+
+func spanAttribute(span sdktrace.ReadOnlySpan, key attribute.Key) (attribute.Value, bool) {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+func TestServerSpanParentLinkage(t *testing.T) {
+	tests := []struct {
+		name        string
+		traceParent string
+		wantRemote  bool
+	}{
+		{name: "inbound traceparent", traceParent: "00-" + testTraceID + "-" + testParentSpanID + "-01", wantRemote: true},
+		{name: "no traceparent", traceParent: "", wantRemote: false},
+		{name: "malformed traceparent", traceParent: "00-zz-" + testParentSpanID + "-01", wantRemote: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, recorder := newSpanTestRouter(t, &TraceConfig{})
+			header := http.Header{}
+			if tt.traceParent != "" {
+				header.Set(HeaderTraceParent, tt.traceParent)
+			}
+			serve(r, "/items/1", header)
+
+			span := endedSpan(t, recorder)
+			parent := span.Parent()
+			if parent.IsRemote() != tt.wantRemote {
+				t.Fatalf("parent remote = %v, want %v", parent.IsRemote(), tt.wantRemote)
+			}
+			if !tt.wantRemote {
+				if parent.IsValid() {
+					t.Fatalf("root span has parent %s", parent.SpanID())
+				}
+				return
+			}
+			if got := span.SpanContext().TraceID().String(); got != testTraceID {
+				t.Errorf("trace id = %s, want %s", got, testTraceID)
+			}
+			if got := parent.SpanID().String(); got != testParentSpanID {
+				t.Errorf("parent span id = %s, want %s", got, testParentSpanID)
+			}
+		})
+	}
+}
+
+func TestServerSpanStatus(t *testing.T) {
+	tests := []struct {
+		path       string
+		wantCode   codes.Code
+		wantStatus int
+		wantPanic  bool
+	}{
+		{path: "/items/1", wantCode: codes.Unset, wantStatus: http.StatusOK},
+		{path: "/missing", wantCode: codes.Unset, wantStatus: http.StatusNotFound},
+		{path: "/fail", wantCode: codes.Error, wantStatus: http.StatusServiceUnavailable},
+		{path: "/panic", wantCode: codes.Error, wantStatus: http.StatusInternalServerError, wantPanic: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			r, recorder := newSpanTestRouter(t, &TraceConfig{})
+			if w := serve(r, tt.path, nil); w.Code != tt.wantStatus {
+				t.Fatalf("response status = %d, want %d", w.Code, tt.wantStatus)
+			}
+
+			span := endedSpan(t, recorder)
+			if span.Status().Code != tt.wantCode {
+				t.Errorf("span status = %v, want %v", span.Status().Code, tt.wantCode)
+			}
+			if v, _ := spanAttribute(span, semconv.HTTPResponseStatusCodeKey); v.AsInt64() != int64(tt.wantStatus) {
+				t.Errorf("%s = %d, want %d", semconv.HTTPResponseStatusCodeKey, v.AsInt64(), tt.wantStatus)
+			}
+			recordedPanic := false
+			for _, event := range span.Events() {
+				if event.Name == semconv.ExceptionEventName {
+					recordedPanic = true
+				}
+			}
+			if recordedPanic != tt.wantPanic {
+				t.Errorf("exception event recorded = %v, want %v", recordedPanic, tt.wantPanic)
+			}
+		})
+	}
+}
+
+func TestServerSpanAttributes(t *testing.T) {
+	r, recorder := newSpanTestRouter(t, &TraceConfig{})
+	serve(r, "/items/42", http.Header{"User-Agent": []string{"span-test"}})
+
+	span := endedSpan(t, recorder)
+	if span.Name() != "GET /items/:id" {
+		t.Errorf("span name = %q, want %q", span.Name(), "GET /items/:id")
+	}
+	if span.SpanKind() != oteltrace.SpanKindServer {
+		t.Errorf("span kind = %v, want %v", span.SpanKind(), oteltrace.SpanKindServer)
+	}
+	want := map[attribute.Key]string{
+		semconv.HTTPRequestMethodKey: http.MethodGet,
+		semconv.HTTPRouteKey:         "/items/:id",
+		semconv.URLPathKey:           "/items/42",
+		semconv.URLSchemeKey:         "http",
+		semconv.UserAgentOriginalKey: "span-test",
+	}
+	for key, value := range want {
+		got, ok := spanAttribute(span, key)
+		if !ok || got.AsString() != value {
+			t.Errorf("%s = %q, want %q", key, got.AsString(), value)
+		}
+	}
+}
+
+func TestServerSpanHonorsHeadSampling(t *testing.T) {
+	tests := []struct {
+		name        string
+		rate        float64
+		sampler     sdktrace.Sampler
+		wantSpans   int
+		wantSampled bool
+	}{
+		{name: "unsampled head decision", rate: 0, sampler: sdktrace.AlwaysSample(), wantSpans: 0, wantSampled: false},
+		{name: "sampled head decision", rate: 1, sampler: sdktrace.AlwaysSample(), wantSpans: 1, wantSampled: true},
+		{name: "provider drops a sampled request", rate: 1, sampler: sdktrace.NeverSample(), wantSpans: 0, wantSampled: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &TraceConfig{
+				Sampling:            &SamplingConfig{DefaultRate: tt.rate},
+				ResponsePropagators: []Propagator{W3CPropagator{}},
+			}
+			r, recorder := newSpanTestRouter(t, config, sdktrace.WithSampler(tt.sampler))
+			w := serve(r, "/span-context", nil)
+
+			if got := len(recorder.Ended()); got != tt.wantSpans {
+				t.Errorf("ended spans = %d, want %d", got, tt.wantSpans)
+			}
+			tp, err := ParseTraceParent(w.Header().Get(HeaderTraceParent))
+			if err != nil {
+				t.Fatalf("response traceparent: %v", err)
+			}
+			if sampled := tp.Flags&flagSampled != 0; sampled != tt.wantSampled {
+				t.Errorf("response traceparent sampled = %v, want %v", sampled, tt.wantSampled)
+			}
+			if got := w.Body.String(); got != tp.TraceID {
+				t.Errorf("handler span context trace id = %s, want %s", got, tp.TraceID)
+			}
+		})
+	}
+}
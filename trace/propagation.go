@@ -0,0 +1,208 @@
+package trace
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	cx "github.com/piyushkumar96/common-middlewares/context"
+)
+
+// SpanContext is the propagation-format independent form of an inbound or outbound trace context.
+type SpanContext struct {
+	TraceID    string // 32 lowercase hex chars
+	SpanID     string // 16 lowercase hex chars
+	Sampled    bool
+	TraceState string // W3C only
+}
+
+// Propagator extracts a SpanContext from, and injects one into, HTTP headers in one wire format.
+type Propagator interface {
+	// Name identifies the format, e.g. "tracecontext", "b3", "b3multi", "jaeger".
+	Name() string
+	// Extract returns false when the format's headers are absent or invalid.
+	Extract(header http.Header) (SpanContext, bool)
+	Inject(sc SpanContext, header http.Header)
+}
+
+// W3CPropagator handles the W3C traceparent/tracestate headers.
+type W3CPropagator struct{}
+
+// B3SinglePropagator handles the Zipkin single "b3" header: {trace}-{span}[-{sampled}[-{parent}]].
+type B3SinglePropagator struct{}
+
+// B3MultiPropagator handles the Zipkin X-B3-* headers.
+type B3MultiPropagator struct{}
+
+// JaegerPropagator handles the "uber-trace-id" header: {trace}:{span}:{parent}:{flags}.
+type JaegerPropagator struct{}
+
+// DefaultPropagators is used when TraceConfig.Propagators is empty.
+func DefaultPropagators() []Propagator {
+	return []Propagator{W3CPropagator{}}
+}
+
+// ExtractSpanContext returns the SpanContext of the first propagator (in priority order) that finds a valid one.
+func ExtractSpanContext(header http.Header, propagators []Propagator) (SpanContext, bool) {
+	for _, propagator := range propagators {
+		if sc, ok := propagator.Extract(header); ok {
+			return sc, true
+		}
+	}
+	return SpanContext{}, false
+}
+
+// SpanContextFromCtxMeta returns this hop's SpanContext as recorded in ctxMeta.
+func SpanContextFromCtxMeta(ctxMeta *cx.CtxMeta) SpanContext {
+	return SpanContext{TraceID: ctxMeta.TraceID, SpanID: ctxMeta.SpanID, Sampled: ctxMeta.Sampled, TraceState: ctxMeta.TraceState}
+}
+
+// InjectTraceHeaders writes the request's trace context (from CtxMeta in ctx) into header using every propagator,
+// plus its W3C baggage, e.g. before sending an outbound request. No trace headers are written when ctx carries
+// no trace ID.
+func InjectTraceHeaders(ctx context.Context, header http.Header, propagators ...Propagator) {
+	InjectBaggage(ctx, header)
+	ctxMeta := cx.GetContextMeta(ctx)
+	if ctxMeta.TraceID == "" || ctxMeta.SpanID == "" {
+		return
+	}
+	if len(propagators) == 0 {
+		propagators = DefaultPropagators()
+	}
+	sc := SpanContextFromCtxMeta(ctxMeta)
+	for _, propagator := range propagators {
+		propagator.Inject(sc, header)
+	}
+}
+
+func (W3CPropagator) Name() string { return "tracecontext" }
+
+func (W3CPropagator) Extract(header http.Header) (SpanContext, bool) {
+	tp, err := ParseTraceParent(header.Get(HeaderTraceParent))
+	if err != nil {
+		return SpanContext{}, false
+	}
+	sc := SpanContext{TraceID: tp.TraceID, SpanID: tp.ParentID, Sampled: tp.Sampled()}
+	if traceState := header.Get(HeaderTraceState); len(traceState) <= maxTraceStateLen {
+		sc.TraceState = traceState
+	}
+	return sc, true
+}
+
+func (W3CPropagator) Inject(sc SpanContext, header http.Header) {
+	tp := &TraceParent{TraceID: sc.TraceID, ParentID: sc.SpanID}
+	if sc.Sampled {
+		tp.Flags = flagSampled
+	}
+	header.Set(HeaderTraceParent, tp.String())
+	if sc.TraceState != "" {
+		header.Set(HeaderTraceState, sc.TraceState)
+	}
+}
+
+func (B3SinglePropagator) Name() string { return "b3" }
+
+func (B3SinglePropagator) Extract(header http.Header) (SpanContext, bool) {
+	parts := strings.Split(strings.TrimSpace(header.Get(HeaderB3)), "-")
+	// A lone sampling state ("0", "1", "d") carries no IDs to join.
+	if len(parts) < 2 || len(parts) > 4 {
+		return SpanContext{}, false
+	}
+	traceID, okTrace := normalizeID(parts[0], traceIDHexLen)
+	spanID, okSpan := normalizeID(parts[1], spanIDHexLen)
+	if !okTrace || !okSpan {
+		return SpanContext{}, false
+	}
+	sampled := true
+	if len(parts) > 2 {
+		switch parts[2] {
+		case "1", "d":
+		case "0":
+			sampled = false
+		default:
+			return SpanContext{}, false
+		}
+	}
+	return SpanContext{TraceID: traceID, SpanID: spanID, Sampled: sampled}, true
+}
+
+func (B3SinglePropagator) Inject(sc SpanContext, header http.Header) {
+	header.Set(HeaderB3, fmt.Sprintf("%s-%s-%s", sc.TraceID, sc.SpanID, b3Sampled(sc.Sampled)))
+}
+
+func (B3MultiPropagator) Name() string { return "b3multi" }
+
+func (B3MultiPropagator) Extract(header http.Header) (SpanContext, bool) {
+	traceID, okTrace := normalizeID(header.Get(HeaderB3TraceID), traceIDHexLen)
+	spanID, okSpan := normalizeID(header.Get(HeaderB3SpanID), spanIDHexLen)
+	if !okTrace || !okSpan {
+		return SpanContext{}, false
+	}
+	sampled := true
+	switch strings.ToLower(header.Get(HeaderB3Sampled)) {
+	case "", "1", "true":
+	case "0", "false":
+		sampled = false
+	default:
+		return SpanContext{}, false
+	}
+	if header.Get(HeaderB3Flags) == "1" {
+		sampled = true // debug implies sampled
+	}
+	return SpanContext{TraceID: traceID, SpanID: spanID, Sampled: sampled}, true
+}
+
+func (B3MultiPropagator) Inject(sc SpanContext, header http.Header) {
+	header.Set(HeaderB3TraceID, sc.TraceID)
+	header.Set(HeaderB3SpanID, sc.SpanID)
+	header.Set(HeaderB3Sampled, b3Sampled(sc.Sampled))
+}
+
+func (JaegerPropagator) Name() string { return "jaeger" }
+
+func (JaegerPropagator) Extract(header http.Header) (SpanContext, bool) {
+	value := header.Get(HeaderUberTraceID)
+	if unescaped, err := url.QueryUnescape(value); err == nil {
+		value = unescaped
+	}
+	parts := strings.Split(strings.TrimSpace(value), ":")
+	if len(parts) != 4 {
+		return SpanContext{}, false
+	}
+	traceID, okTrace := normalizeID(parts[0], traceIDHexLen)
+	spanID, okSpan := normalizeID(parts[1], spanIDHexLen)
+	flags, err := strconv.ParseUint(parts[3], 16, 8)
+	if !okTrace || !okSpan || err != nil {
+		return SpanContext{}, false
+	}
+	return SpanContext{TraceID: traceID, SpanID: spanID, Sampled: flags&uint64(flagSampled) != 0}, true
+}
+
+func (JaegerPropagator) Inject(sc SpanContext, header http.Header) {
+	flags := "0"
+	if sc.Sampled {
+		flags = "1"
+	}
+	header.Set(HeaderUberTraceID, fmt.Sprintf("%s:%s:0:%s", sc.TraceID, sc.SpanID, flags))
+}
+
+// normalizeID lowercases and left-pads a hex ID (B3 and Jaeger allow 64-bit trace IDs and unpadded values)
+// and rejects anything that is not valid, non-zero hex of at most hexLen chars.
+func normalizeID(id string, hexLen int) (string, bool) {
+	id = strings.ToLower(strings.TrimSpace(id))
+	if id == "" || len(id) > hexLen {
+		return "", false
+	}
+	id = strings.Repeat("0", hexLen-len(id)) + id
+	return id, isValidID(id, hexLen)
+}
+
+func b3Sampled(sampled bool) string {
+	if sampled {
+		return "1"
+	}
+	return "0"
+}
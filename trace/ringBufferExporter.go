@@ -0,0 +1,93 @@
+package trace
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// RingBufferExporter keeps the most recent trace records in memory, for tests and the debug endpoint.
+type RingBufferExporter struct {
+	mu      sync.RWMutex
+	records []*TraceSummary
+	next    int
+	full    bool
+}
+
+// NewRingBufferExporter keeps up to capacity records (0 uses the default, 1000).
+func NewRingBufferExporter(capacity int) *RingBufferExporter {
+	if capacity <= 0 {
+		capacity = defaultRingBufferCapacity
+	}
+	return &RingBufferExporter{records: make([]*TraceSummary, capacity)}
+}
+
+// Export stores records, overwriting the oldest ones once the buffer is full.
+func (r *RingBufferExporter) Export(records []*TraceSummary) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, record := range records {
+		r.records[r.next] = record
+		r.next = (r.next + 1) % len(r.records)
+		if r.next == 0 {
+			r.full = true
+		}
+	}
+	return nil
+}
+
+// Close is a no-op; the records stay readable.
+func (r *RingBufferExporter) Close() error {
+	return nil
+}
+
+// Records returns up to limit records, newest first (limit <= 0 returns all).
+func (r *RingBufferExporter) Records(limit int) []*TraceSummary {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	count := r.next
+	if r.full {
+		count = len(r.records)
+	}
+	if limit > 0 && limit < count {
+		count = limit
+	}
+	records := make([]*TraceSummary, 0, count)
+	for i := 1; i <= count; i++ {
+		records = append(records, r.records[(r.next-i+len(r.records))%len(r.records)])
+	}
+	return records
+}
+
+// FindByRequestID returns the records of requestID, newest first.
+func (r *RingBufferExporter) FindByRequestID(requestID string) []*TraceSummary {
+	var found []*TraceSummary
+	for _, record := range r.Records(0) {
+		if record.RequestID == requestID {
+			found = append(found, record)
+		}
+	}
+	return found
+}
+
+// FindByIdentifier returns the records whose identifier mappings hold key=value, newest first.
+func (r *RingBufferExporter) FindByIdentifier(key, value string) []*TraceSummary {
+	var found []*TraceSummary
+	for _, record := range r.Records(0) {
+		if mapped, ok := record.IdentifierMappings[key]; ok && fmt.Sprint(mapped) == value {
+			found = append(found, record)
+		}
+	}
+	return found
+}
+
+// RequestIDsByIdentifier returns the distinct request IDs that carried key=value, newest first.
+func (r *RingBufferExporter) RequestIDsByIdentifier(key, value string) []string {
+	var requestIDs []string
+	for _, record := range r.FindByIdentifier(key, value) {
+		if !slices.Contains(requestIDs, record.RequestID) {
+			requestIDs = append(requestIDs, record.RequestID)
+		}
+	}
+	return requestIDs
+}
@@ -0,0 +1,155 @@
+package trace
+
+import (
+	"context"
+	"math/rand/v2"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	cx "github.com/piyushkumar96/common-middlewares/context"
+)
+
+// SamplingConfig decides which requests get full trace-event capture and summary logging.
+// Unsampled requests still record warn and error events, and propagate sampled=0 downstream.
+type SamplingConfig struct {
+	// ParentBased follows the sampled flag of a valid inbound trace context.
+	ParentBased bool
+	// DefaultRate is the fraction (0..1) of requests sampled when no parent decision applies.
+	DefaultRate float64
+	// RouteRates overrides DefaultRate per route template (gin FullPath), e.g. {"/health": 0}.
+	RouteRates map[string]float64
+	// MaxTracesPerSecond caps head-sampled traces across the process (0 means no cap).
+	MaxTracesPerSecond float64
+	// AlwaysSampleErrors upgrades unsampled requests that end with a 5xx status or recorded errors.
+	AlwaysSampleErrors bool
+}
+
+// SamplingDecision is stored in the request context; handlers can check it to skip expensive debug work.
+type SamplingDecision struct {
+	mu      sync.RWMutex
+	sampled bool
+	reason  string
+}
+
+// Sampled reports the current decision (it may be upgraded after the handlers ran, see AlwaysSampleErrors).
+func (d *SamplingDecision) Sampled() bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.sampled
+}
+
+// Reason returns why the decision was taken (see the SamplingReason constants).
+func (d *SamplingDecision) Reason() string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.reason
+}
+
+func (d *SamplingDecision) set(sampled bool, reason string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.sampled, d.reason = sampled, reason
+}
+
+// GetSamplingDecision returns the decision stored in ctx. Without sampling configured every request is sampled.
+func GetSamplingDecision(ctx context.Context) *SamplingDecision {
+	if decision, ok := ctx.Value(cx.SamplingDecisionKey).(*SamplingDecision); ok {
+		return decision
+	}
+	return &SamplingDecision{sampled: true, reason: SamplingReasonAlways}
+}
+
+// IsSampled is shorthand for GetSamplingDecision(ctx).Sampled().
+func IsSampled(ctx context.Context) bool {
+	return GetSamplingDecision(ctx).Sampled()
+}
+
+// sampler applies a SamplingConfig; one instance is shared by all requests of a middleware.
+type sampler struct {
+	config  *SamplingConfig
+	limiter *tokenBucket
+}
+
+func newSampler(config *SamplingConfig) *sampler {
+	if config == nil {
+		return nil
+	}
+	s := &sampler{config: config}
+	if config.MaxTracesPerSecond > 0 {
+		s.limiter = newTokenBucket(config.MaxTracesPerSecond)
+	}
+	return s
+}
+
+// decideHead takes the decision before the handlers run; hasParent tells whether an inbound context was found.
+func (s *sampler) decideHead(gc *gin.Context, ctxMeta *cx.CtxMeta, hasParent bool) *SamplingDecision {
+	decision := &SamplingDecision{}
+	switch {
+	case s.config.ParentBased && hasParent:
+		decision.set(ctxMeta.Sampled, SamplingReasonParent)
+	default:
+		rate, reason := s.config.DefaultRate, SamplingReasonDefaultRate
+		if routeRate, ok := s.config.RouteRates[gc.FullPath()]; ok {
+			rate, reason = routeRate, SamplingReasonRouteRate
+		}
+		decision.set(rate > 0 && rand.Float64() < rate, reason)
+	}
+	if decision.sampled && s.limiter != nil && !s.limiter.allow(time.Now()) {
+		decision.set(false, SamplingReasonRateLimited)
+	}
+	return decision
+}
+
+// decideTail upgrades an unsampled request that failed, when AlwaysSampleErrors is set.
+func (s *sampler) decideTail(gc *gin.Context, decision *SamplingDecision, traceMeta *cx.TraceMeta) {
+	if !s.config.AlwaysSampleErrors || decision.Sampled() {
+		return
+	}
+	if gc.Writer.Status() >= http.StatusInternalServerError || len(gc.Errors) > 0 || len(traceMeta.Errors()) > 0 {
+		decision.set(true, SamplingReasonError)
+	}
+}
+
+// applySampled records the head decision on ctxMeta so it is propagated downstream.
+func applySampled(ctxMeta *cx.CtxMeta, sampled bool) {
+	ctxMeta.Sampled = sampled
+	tp := &TraceParent{TraceID: ctxMeta.TraceID, ParentID: ctxMeta.SpanID}
+	if sampled {
+		tp.Flags = flagSampled
+	}
+	ctxMeta.TraceParent = tp.String()
+}
+
+// tokenBucket allows up to rate events per second with a burst of max(1, rate).
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64) *tokenBucket {
+	burst := rate
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{rate: rate, burst: burst, tokens: burst, last: time.Now()}
+}
+
+func (b *tokenBucket) allow(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
@@ -0,0 +1,221 @@
+package trace
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	cx "github.com/piyushkumar96/common-middlewares/context"
+)
+
+// ServerTimingMode decides how the Server-Timing header reaches the client.
+type ServerTimingMode string
+
+const (
+	// ServerTimingBuffered buffers the response body so the header can be added before it is sent.
+	// Responses larger than MaxBufferBytes, and handlers that flush or hijack, fall back to streaming without it.
+	ServerTimingBuffered ServerTimingMode = "buffered"
+	// ServerTimingTrailer announces Server-Timing as a trailer and sends it after the body (HTTP/2 or chunked
+	// HTTP/1.1 only; browsers may ignore trailers).
+	ServerTimingTrailer ServerTimingMode = "trailer"
+)
+
+// ServerTimingConfig enables the Server-Timing response header built from TraceMeta events.
+type ServerTimingConfig struct {
+	Mode ServerTimingMode
+	// Allowlist maps trace event names to the metric names exposed to clients (e.g. "db_query" -> "db").
+	// Events not listed are never exposed; durations of events sharing a metric name are summed.
+	Allowlist map[string]string
+	// IncludeTotal adds a "total" metric with the time spent in the handler chain.
+	IncludeTotal bool
+	// MaxBufferBytes caps the buffered body in ServerTimingBuffered mode (0 uses the default).
+	MaxBufferBytes int
+}
+
+// serverTimingValue renders the header value from the recorded events, filtered by the allowlist.
+func serverTimingValue(config *ServerTimingConfig, traceMeta *cx.TraceMeta, total time.Duration) string {
+	durations := make(map[string]time.Duration)
+	order := make([]string, 0)
+	for _, event := range traceMeta.Events() {
+		metric, ok := config.Allowlist[event.Name]
+		if !ok || event.Duration <= 0 || !isToken(metric) {
+			continue
+		}
+		if _, seen := durations[metric]; !seen {
+			order = append(order, metric)
+		}
+		durations[metric] += event.Duration
+	}
+	metrics := make([]string, 0, len(order)+1)
+	for _, metric := range order {
+		metrics = append(metrics, formatServerTiming(metric, durations[metric]))
+	}
+	if config.IncludeTotal {
+		metrics = append(metrics, formatServerTiming(serverTimingTotal, total))
+	}
+	return strings.Join(metrics, ", ")
+}
+
+func formatServerTiming(metric string, duration time.Duration) string {
+	return fmt.Sprintf("%s;dur=%.1f", metric, float64(duration.Microseconds())/1000)
+}
+
+func isToken(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c <= ' ' || c >= 0x7f || strings.IndexByte(`"(),/:;<=>?@[\]{}`, c) >= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// runWithServerTiming runs next with Server-Timing enabled. The finish is deferred, so gc.Writer is restored
+// and the buffered response released even when next panics.
+func runWithServerTiming(config *ServerTimingConfig, gc *gin.Context, traceMeta *cx.TraceMeta, next func()) {
+	finish := startServerTiming(config, gc, traceMeta)
+	defer finish()
+	next()
+}
+
+// startServerTiming prepares the response for Server-Timing; the returned func must run after gc.Next().
+func startServerTiming(config *ServerTimingConfig, gc *gin.Context, traceMeta *cx.TraceMeta) func() {
+	start := time.Now()
+	value := func() string { return serverTimingValue(config, traceMeta, time.Since(start)) }
+
+	if config.Mode == ServerTimingTrailer {
+		gc.Writer.Header().Add("Trailer", HeaderServerTiming)
+		return func() {
+			if v := value(); v != "" {
+				// Before anything was sent this is a normal header, afterwards it is the declared trailer.
+				gc.Writer.Header().Set(HeaderServerTiming, v)
+			}
+		}
+	}
+
+	maxBytes := config.MaxBufferBytes
+	if maxBytes <= 0 {
+		maxBytes = defaultServerTimingBufferBytes
+	}
+	writer := &bufferedTimingWriter{ResponseWriter: gc.Writer, maxBytes: maxBytes, status: gc.Writer.Status()}
+	gc.Writer = writer
+	return func() {
+		gc.Writer = writer.ResponseWriter
+		if writer.passthrough {
+			return
+		}
+		if v := value(); v != "" {
+			writer.ResponseWriter.Header().Set(HeaderServerTiming, v)
+		}
+		writer.release()
+	}
+}
+
+// bufferedTimingWriter holds the status and body back until the Server-Timing header is known.
+type bufferedTimingWriter struct {
+	gin.ResponseWriter
+	maxBytes    int
+	status      int
+	headerNow   bool
+	body        bytes.Buffer
+	passthrough bool
+}
+
+func (w *bufferedTimingWriter) WriteHeader(code int) {
+	if w.passthrough {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	if code > 0 && !w.headerNow {
+		w.status = code
+	}
+}
+
+func (w *bufferedTimingWriter) WriteHeaderNow() {
+	if w.passthrough {
+		w.ResponseWriter.WriteHeaderNow()
+		return
+	}
+	w.headerNow = true
+}
+
+func (w *bufferedTimingWriter) Write(b []byte) (int, error) {
+	if w.passthrough {
+		return w.ResponseWriter.Write(b)
+	}
+	w.headerNow = true
+	if w.body.Len()+len(b) > w.maxBytes {
+		w.startPassthrough()
+		return w.ResponseWriter.Write(b)
+	}
+	return w.body.Write(b)
+}
+
+func (w *bufferedTimingWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *bufferedTimingWriter) Status() int {
+	if w.passthrough {
+		return w.ResponseWriter.Status()
+	}
+	return w.status
+}
+
+func (w *bufferedTimingWriter) Size() int {
+	if w.passthrough {
+		return w.ResponseWriter.Size()
+	}
+	if !w.headerNow {
+		return -1
+	}
+	return w.body.Len()
+}
+
+func (w *bufferedTimingWriter) Written() bool {
+	if w.passthrough {
+		return w.ResponseWriter.Written()
+	}
+	return w.headerNow
+}
+
+func (w *bufferedTimingWriter) Flush() {
+	w.startPassthrough()
+	w.ResponseWriter.Flush()
+}
+
+func (w *bufferedTimingWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.startPassthrough()
+	return w.ResponseWriter.Hijack()
+}
+
+// startPassthrough sends what was buffered (without Server-Timing) and stops buffering.
+func (w *bufferedTimingWriter) startPassthrough() {
+	if w.passthrough {
+		return
+	}
+	w.release()
+	w.passthrough = true
+}
+
+// release writes the held status and body to the underlying writer.
+func (w *bufferedTimingWriter) release() {
+	w.ResponseWriter.WriteHeader(w.status)
+	if w.headerNow {
+		w.ResponseWriter.WriteHeaderNow()
+	}
+	if w.body.Len() > 0 {
+		_, _ = w.ResponseWriter.Write(w.body.Bytes())
+		w.body.Reset()
+	}
+}
+
+var _ http.Flusher = (*bufferedTimingWriter)(nil)
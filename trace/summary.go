@@ -0,0 +1,137 @@
+package trace
+
+import (
+	"encoding/json"
+	"math/rand/v2"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	cx "github.com/piyushkumar96/common-middlewares/context"
+	l "github.com/piyushkumar96/generic-logger"
+)
+
+// SummaryConfig enables one summary log record per request, written after the handlers ran.
+type SummaryConfig struct {
+	// SuccessSampleRate is the fraction (0..1) of successful requests summarized. Failed requests (5xx status,
+	// gin errors or TraceMeta errors) are always summarized.
+	SuccessSampleRate float64
+	// MaxRecordBytes caps the JSON size of a record; events are dropped from the end to fit (0 uses the default).
+	MaxRecordBytes int
+}
+
+// TraceSummary is the record emitted per sampled request.
+type TraceSummary struct {
+	RequestID          string                 `json:"request_id"`
+	TraceID            string                 `json:"trace_id,omitempty"`
+	Method             string                 `json:"method"`
+	Route              string                 `json:"route"`
+	StartTime          time.Time              `json:"start_time"`
+	Status             int                    `json:"status"`
+	LatencyMillis      float64                `json:"latency_ms"`
+	Events             []cx.TraceEvent        `json:"events,omitempty"`
+	Errors             []string               `json:"errors,omitempty"`
+	IdentifierMappings map[string]interface{} `json:"identifier_mappings,omitempty"`
+	Truncated          bool                   `json:"truncated,omitempty"`
+	DroppedEvents      int                    `json:"dropped_events,omitempty"`
+}
+
+// summarySelected reports whether a finished request is summarized. Failed requests always are; for the others a
+// non-nil decision (TraceConfig.Sampling) replaces SuccessSampleRate.
+func summarySelected(config *SummaryConfig, failed bool, decision *SamplingDecision) bool {
+	if failed {
+		return true
+	}
+	if decision != nil {
+		return decision.Sampled()
+	}
+	return config.SuccessSampleRate > 0 && rand.Float64() < config.SuccessSampleRate
+}
+
+// emitSummary logs the summary of a finished request, as a warning when the request failed.
+func emitSummary(summary *TraceSummary, failed bool) {
+	if l.Logger == nil {
+		return
+	}
+	fields := append([]interface{}{cx.ReqIDKey, summary.RequestID, "summary", summary}, cx.IdentifierFields(summary.IdentifierMappings)...)
+	if failed {
+		l.Logger.Warn(summaryLogMessage, fields...)
+	} else {
+		l.Logger.Info(summaryLogMessage, fields...)
+	}
+}
+
+// requestFailed reports a 5xx status, gin errors or TraceMeta errors.
+func requestFailed(gc *gin.Context, traceMeta *cx.TraceMeta) bool {
+	return gc.Writer.Status() >= http.StatusInternalServerError || len(gc.Errors) > 0 || len(traceMeta.Errors()) > 0
+}
+
+// buildSummary snapshots a finished request into an uncapped TraceSummary; see capSummary.
+func buildSummary(gc *gin.Context, ctxMeta *cx.CtxMeta, traceMeta *cx.TraceMeta, start time.Time) *TraceSummary {
+	snapshot := traceMeta.Snapshot()
+	errs := snapshot.Error
+	for _, ginErr := range gc.Errors {
+		errs = append(errs, ginErr.Error())
+	}
+	route := gc.FullPath()
+	if route == "" {
+		route = gc.Request.URL.Path
+	}
+	summary := &TraceSummary{
+		RequestID:          ctxMeta.ReqID,
+		TraceID:            ctxMeta.TraceID,
+		Method:             gc.Request.Method,
+		Route:              route,
+		StartTime:          start,
+		Status:             gc.Writer.Status(),
+		LatencyMillis:      float64(time.Since(start).Microseconds()) / 1000,
+		Events:             snapshot.Events,
+		Errors:             errs,
+		IdentifierMappings: snapshot.IdentifierMappings,
+	}
+	return summary
+}
+
+// capSummary returns a copy of summary capped at maxBytes (0 uses the default), leaving summary untouched
+// so one snapshot can serve consumers with different caps.
+func capSummary(summary *TraceSummary, maxBytes int) *TraceSummary {
+	if maxBytes <= 0 {
+		maxBytes = defaultSummaryMaxBytes
+	}
+	capped := *summary
+	fitSummary(&capped, maxBytes)
+	return &capped
+}
+
+// fitSummary drops events from the end (then errors, and identifier mappings last) until the record fits maxBytes.
+func fitSummary(summary *TraceSummary, maxBytes int) {
+	size := func() int {
+		encoded, _ := json.Marshal(summary)
+		return len(encoded)
+	}
+	if size() <= maxBytes {
+		return
+	}
+	summary.Truncated = true
+	total := len(summary.Events)
+	// binary search the largest event prefix that fits
+	lo, hi := 0, total
+	events := summary.Events
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		summary.Events = events[:mid]
+		if size() <= maxBytes {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	summary.Events = events[:lo]
+	summary.DroppedEvents = total - lo
+	for len(summary.Errors) > 0 && size() > maxBytes {
+		summary.Errors = summary.Errors[:len(summary.Errors)-1]
+	}
+	if size() > maxBytes {
+		summary.IdentifierMappings = nil
+	}
+}
@@ -0,0 +1,28 @@
+package trace
+
+import "testing"
+
+func TestSummarySelected(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   *SummaryConfig
+		failed   bool
+		decision *SamplingDecision
+		want     bool
+	}{
+		{name: "failed without sampling", config: &SummaryConfig{}, failed: true, want: true},
+		{name: "success without sample rate", config: &SummaryConfig{}, want: false},
+		{name: "success with full sample rate", config: &SummaryConfig{SuccessSampleRate: 1}, want: true},
+		{name: "sampled decision", config: &SummaryConfig{}, decision: &SamplingDecision{sampled: true}, want: true},
+		{name: "unsampled decision", config: &SummaryConfig{SuccessSampleRate: 1}, decision: &SamplingDecision{}, want: false},
+		// Sampling without AlwaysSampleErrors leaves a failed request unsampled; it is still summarized
+		{name: "failed with unsampled decision", config: &SummaryConfig{}, failed: true, decision: &SamplingDecision{}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := summarySelected(tt.config, tt.failed, tt.decision); got != tt.want {
+				t.Errorf("summarySelected() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -3,35 +3,139 @@ package trace
 import (
 	"context"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	im "github.com/piyushkumar96/app-monitoring/interfaces"
 	cx "github.com/piyushkumar96/common-middlewares/context"
+	l "github.com/piyushkumar96/generic-logger"
+	oteltrace "go.opentelemetry.io/otel/trace"
 )
 
+// TraceConfig configures the TraceWithConfig middleware
+type TraceConfig struct {
+	AppMetrics im.AppMetricsInterface
+	// RequestID overrides the process-wide request ID configuration (see context.SetRequestIDConfig).
+	RequestID *cx.RequestIDConfig
+	// EnableOTelSpans starts an OpenTelemetry server span per request (per head-sampled request when Sampling is set).
+	EnableOTelSpans bool
+	// TracerProvider is used for the server spans; nil falls back to otel.GetTracerProvider().
+	TracerProvider oteltrace.TracerProvider
+	// Propagators extract the inbound trace context, first match wins; empty means DefaultPropagators().
+	Propagators []Propagator
+	// BaggagePromotedKeys are W3C baggage keys copied into CtxMeta.Baggage (and therefore into logs).
+	BaggagePromotedKeys []string
+	// ResponsePropagators inject this hop's trace context into the response headers (none when empty).
+	ResponsePropagators []Propagator
+	// ServerTiming, when set, emits a Server-Timing header from the allowlisted trace events.
+	ServerTiming *ServerTimingConfig
+	// Summary, when set, logs one trace summary record per (sampled or failed) request after the handlers ran.
+	Summary *SummaryConfig
+	// Exporter, when set, receives one trace record per (sampled) request, see NewBatchExporter.
+	Exporter *BatchExporter
+	// Options is the OPTIONS request policy; empty means OptionsShortCircuit.
+	Options OptionsMode
+	// OptionsHandler handles OPTIONS requests when Options is OptionsDelegate (typically a CORS middleware);
+	// without it OPTIONS requests are short-circuited and the misconfiguration is logged at setup.
+	OptionsHandler gin.HandlerFunc
+	// Sampling, when set, decides per request whether trace events are fully captured and summarized.
+	Sampling *SamplingConfig
+}
+
 // Trace will generate req-id middleware
 func Trace(appMetrics im.AppMetricsInterface) gin.HandlerFunc {
+	return TraceWithConfig(&TraceConfig{AppMetrics: appMetrics})
+}
+
+// TraceWithConfig is Trace with optional features (e.g. OpenTelemetry server spans) enabled through TraceConfig.
+func TraceWithConfig(config *TraceConfig) gin.HandlerFunc {
+	tracer := newServerTracer(config)
+	smp := newSampler(config.Sampling)
+	checkOptionsConfig(config)
+	propagators := config.Propagators
+	if len(propagators) == 0 {
+		propagators = DefaultPropagators()
+	}
 	return func(gc *gin.Context) {
-		if gc.Request.Method == http.MethodOptions {
-			gc.AbortWithStatus(http.StatusNoContent)
-		} else {
-			ctx := context.Background()
+		start := time.Now()
+		ctx := gc.Request.Context()
+
+		/** initialize context meta */
+		if config.RequestID != nil {
+			cx.ResolveRequestIDWithConfig(gc, config.RequestID)
+		}
+		key, cm := cx.InitContextMeta(gc, "")
+		inbound, ok := ExtractSpanContext(gc.Request.Header, propagators)
+		ApplySpanContext(cm, inbound, ok)
+		ctx = context.WithValue(ctx, key, cm)
+		ctx = context.WithValue(ctx, cx.ReqIDKey, cm.ReqID)
 
-			/** initialize context meta */
-			key, cm := cx.InitContextMeta(gc, "")
-			ctx = context.WithValue(ctx, key, cm)
+		/** initialize baggage */
+		baggage, err := ParseBaggage(gc.GetHeader(HeaderBaggage))
+		if err != nil && l.Logger != nil {
+			l.Logger.Debug(ErrInvalidBaggage.Message, "code", ErrInvalidBaggage.Code, "err", err.Error())
+		}
+		PromoteBaggage(cm, baggage, config.BaggagePromotedKeys)
+		ctx = context.WithValue(ctx, cx.BaggageKey, baggage)
+
+		/** initialize response meta */
+		key, rm := cx.InitResponseMeta(gc, config.AppMetrics)
+		ctx = context.WithValue(ctx, key, rm)
 
-			/** initialize response meta */
-			key, rm := cx.InitResponseMeta(gc, appMetrics)
-			ctx = context.WithValue(ctx, key, rm)
+		/** initialize trace meta */
+		key, tm := cx.InitTraceMeta()
+		ctx = context.WithValue(ctx, key, tm)
 
-			/** initialize trace meta */
-			key, tm := cx.InitTraceMeta()
-			ctx = context.WithValue(ctx, key, tm)
+		/** take the head sampling decision */
+		var decision *SamplingDecision
+		if smp != nil {
+			decision = smp.decideHead(gc, cm, ok)
+			applySampled(cm, decision.Sampled())
+			tm.SetRecording(decision.Sampled())
+			ctx = context.WithValue(ctx, cx.SamplingDecisionKey, decision)
+		}
 
-			/** Setting the context in GIN context */
-			gc.Set(cx.CtxKey, ctx)
-			gc.Next()
+		/** start the OpenTelemetry server span */
+		if tracer != nil {
+			var span oteltrace.Span
+			ctx, span = startServerSpan(ctx, tracer, gc, cm, decision)
+			defer endServerSpan(span, gc)
+		}
+
+		/** propagate this hop's trace context to the client */
+		for _, propagator := range config.ResponsePropagators {
+			propagator.Inject(SpanContextFromCtxMeta(cm), gc.Writer.Header())
+		}
+
+		/** Setting the context in GIN context and on the request */
+		cx.SetRequestContext(gc, ctx)
+		next := gc.Next
+		if gc.Request.Method == http.MethodOptions {
+			next = func() { serveOptions(config, gc) }
+		}
+		if config.ServerTiming != nil {
+			runWithServerTiming(config.ServerTiming, gc, tm, next)
+		} else {
+			next()
+		}
+
+		if decision != nil {
+			smp.decideTail(gc, decision, tm)
+		}
+		/** summarize the request once for the summary log and the exporter */
+		var record *TraceSummary
+		if config.Summary != nil {
+			failed := requestFailed(gc, tm)
+			if summarySelected(config.Summary, failed, decision) {
+				record = buildSummary(gc, cm, tm, start)
+				emitSummary(capSummary(record, config.Summary.MaxRecordBytes), failed)
+			}
+		}
+		if config.Exporter != nil && (decision == nil || decision.Sampled()) {
+			if record == nil {
+				record = buildSummary(gc, cm, tm, start)
+			}
+			config.Exporter.Enqueue(capSummary(record, config.Exporter.maxRecordBytes))
 		}
 	}
 }
@@ -0,0 +1,150 @@
+package trace
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+
+	cx "github.com/piyushkumar96/common-middlewares/context"
+	l "github.com/piyushkumar96/generic-logger"
+)
+
+// TraceParent is a parsed W3C traceparent header: version-traceid-parentid-flags.
+type TraceParent struct {
+	Version  byte
+	TraceID  string // 32 lowercase hex chars, not all zero
+	ParentID string // 16 lowercase hex chars, not all zero
+	Flags    byte
+}
+
+// ParseTraceParent parses and validates a traceparent header value.
+// Versions above 00 are accepted as long as the first four fields are valid (forward compatibility).
+func ParseTraceParent(header string) (*TraceParent, error) {
+	header = strings.TrimSpace(header)
+	parts := strings.Split(header, "-")
+	if len(parts) < 4 {
+		return nil, invalidTraceParent(header, "expected 4 dash separated fields")
+	}
+	version, err := decodeHexByte(parts[0])
+	if err != nil || version == invalidVersion {
+		return nil, invalidTraceParent(header, "invalid version")
+	}
+	if version == traceParentVersion && len(parts) != 4 {
+		return nil, invalidTraceParent(header, "version 00 must have exactly 4 fields")
+	}
+	if !isValidID(parts[1], traceIDHexLen) {
+		return nil, invalidTraceParent(header, "trace-id must be 32 lowercase hex chars and not all zero")
+	}
+	if !isValidID(parts[2], spanIDHexLen) {
+		return nil, invalidTraceParent(header, "parent-id must be 16 lowercase hex chars and not all zero")
+	}
+	flags, err := decodeHexByte(parts[3])
+	if err != nil {
+		return nil, invalidTraceParent(header, "invalid trace-flags")
+	}
+	return &TraceParent{Version: version, TraceID: parts[1], ParentID: parts[2], Flags: flags}, nil
+}
+
+// NewRootTraceParent starts a new trace with a random trace ID and span ID.
+func NewRootTraceParent(sampled bool) *TraceParent {
+	tp := &TraceParent{Version: traceParentVersion, TraceID: NewTraceID(), ParentID: NewSpanID()}
+	if sampled {
+		tp.Flags |= flagSampled
+	}
+	return tp
+}
+
+// Sampled reports whether the sampled flag is set.
+func (tp *TraceParent) Sampled() bool {
+	return tp.Flags&flagSampled == flagSampled
+}
+
+// String formats the traceparent as version 00.
+func (tp *TraceParent) String() string {
+	return fmt.Sprintf("%02x-%s-%s-%02x", traceParentVersion, tp.TraceID, tp.ParentID, tp.Flags)
+}
+
+// NewTraceID returns a random, non-zero 16 byte trace ID as 32 hex chars.
+func NewTraceID() string {
+	return randomHexID(traceIDHexLen / 2)
+}
+
+// NewSpanID returns a random, non-zero 8 byte span ID as 16 hex chars.
+func NewSpanID() string {
+	return randomHexID(spanIDHexLen / 2)
+}
+
+// ApplyTraceContext parses the inbound traceparent/tracestate into ctxMeta (see ApplySpanContext).
+func ApplyTraceContext(ctxMeta *cx.CtxMeta, traceParentHeader, traceStateHeader string) {
+	header := http.Header{}
+	header.Set(HeaderTraceParent, traceParentHeader)
+	header.Set(HeaderTraceState, traceStateHeader)
+	inbound, ok := W3CPropagator{}.Extract(header)
+	if !ok && traceParentHeader != "" && l.Logger != nil {
+		l.Logger.Debug(ErrInvalidTraceParent.Message, "code", ErrInvalidTraceParent.Code, "traceparent", traceParentHeader)
+	}
+	ApplySpanContext(ctxMeta, inbound, ok)
+}
+
+// ApplySpanContext records the trace context of this hop in ctxMeta. Without a valid inbound context (ok false)
+// a new root trace is started and tracestate is dropped, as the spec requires. In both cases a fresh span ID is
+// created for this hop, and ctxMeta.TraceParent is set to the W3C value to propagate downstream.
+func ApplySpanContext(ctxMeta *cx.CtxMeta, inbound SpanContext, ok bool) {
+	if !ok {
+		root := NewRootTraceParent(true)
+		inbound = SpanContext{TraceID: root.TraceID, Sampled: root.Sampled()}
+	}
+	hop := &TraceParent{Version: traceParentVersion, TraceID: inbound.TraceID, ParentID: NewSpanID()}
+	if inbound.Sampled {
+		hop.Flags = flagSampled
+	}
+	ctxMeta.TraceID = hop.TraceID
+	ctxMeta.ParentSpanID = inbound.SpanID
+	ctxMeta.SpanID = hop.ParentID
+	ctxMeta.Sampled = hop.Sampled()
+	ctxMeta.TraceState = inbound.TraceState
+	ctxMeta.TraceParent = hop.String()
+}
+
+func invalidTraceParent(header, reason string) error {
+	return fmt.Errorf("%s: %s %q: %s", ErrInvalidTraceParent.Code, ErrInvalidTraceParent.Message, header, reason)
+}
+
+func decodeHexByte(s string) (byte, error) {
+	if len(s) != 2 || !isLowerHex(s) {
+		return 0, fmt.Errorf("invalid hex byte %q", s)
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return 0, err
+	}
+	return b[0], nil
+}
+
+func isValidID(s string, hexLen int) bool {
+	return len(s) == hexLen && isLowerHex(s) && strings.Trim(s, "0") != ""
+}
+
+func isLowerHex(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+func randomHexID(size int) string {
+	b := make([]byte, size)
+	for {
+		_, _ = rand.Read(b)
+		for _, v := range b {
+			if v != 0 {
+				return hex.EncodeToString(b)
+			}
+		}
+	}
+}
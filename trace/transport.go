@@ -0,0 +1,178 @@
+package trace
+
+import (
+	"net/http"
+
+	im "github.com/piyushkumar96/app-monitoring/interfaces"
+	"github.com/piyushkumar96/app-monitoring/models"
+	cx "github.com/piyushkumar96/common-middlewares/context"
+)
+
+// OutboundHeader forwards one CtxMeta value to downstream calls; empty values are not sent.
+type OutboundHeader struct {
+	Name  string
+	Value func(ctxMeta *cx.CtxMeta) string
+}
+
+// TransportConfig configures the Transport
+type TransportConfig struct {
+	// Base performs the actual request; nil means http.DefaultTransport.
+	Base http.RoundTripper
+	// ServiceName labels downstream metrics and trace entries; empty uses the request host.
+	ServiceName string
+	// Propagators inject the trace context (with a fresh client span ID); empty means DefaultPropagators().
+	Propagators []Propagator
+	// Headers are the CtxMeta values forwarded to downstream calls; nil means DefaultOutboundHeaders().
+	Headers []OutboundHeader
+	// APIIdentifier labels downstream metrics per endpoint; nil uses the URL path.
+	APIIdentifier func(req *http.Request) string
+	// AppMetrics counts failed calls (ErrOutboundRequestFailed / ErrOutboundServerError); nil falls back to
+	// the AppMetrics in the request's ResponseMeta.
+	AppMetrics im.AppMetricsInterface
+	// DownstreamMetrics records per call latency, status and payload sizes.
+	DownstreamMetrics im.DownstreamServiceMetricsInterface
+}
+
+// Transport is an http.RoundTripper that carries the inbound request context (request ID, user and deployment
+// IDs, trace context, baggage) to downstream calls, and records client timing in TraceMeta and metrics.
+// The outbound request must carry the request context: req.WithContext(context.GetRequestContext(gc)).
+type Transport struct {
+	config *TransportConfig
+}
+
+// DefaultOutboundHeaders forwards the request ID, user ID and deployment ID.
+func DefaultOutboundHeaders() []OutboundHeader {
+	return []OutboundHeader{
+		{Name: HeaderRequestID, Value: func(ctxMeta *cx.CtxMeta) string { return ctxMeta.ReqID }},
+		{Name: string(cx.HeaderUserIDKey), Value: func(ctxMeta *cx.CtxMeta) string { return ctxMeta.UserID }},
+		{Name: HeaderDeploymentID, Value: func(ctxMeta *cx.CtxMeta) string { return ctxMeta.DeploymentID }},
+	}
+}
+
+// NewTransport returns a Transport for config (nil uses the defaults).
+func NewTransport(config *TransportConfig) *Transport {
+	if config == nil {
+		config = &TransportConfig{}
+	}
+	return &Transport{config: config}
+}
+
+// NewHTTPClient returns an http.Client using a Transport for config.
+func NewHTTPClient(config *TransportConfig) *http.Client {
+	return &http.Client{Transport: NewTransport(config)}
+}
+
+// RoundTrip implements http.RoundTripper. The caller's request is not modified, and headers it already carries
+// (request ID, trace context, baggage, ...) are forwarded as set instead of being replaced.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	ctxMeta := cx.GetContextMeta(ctx)
+	outReq := req.Clone(ctx)
+
+	headers := t.config.Headers
+	if headers == nil {
+		headers = DefaultOutboundHeaders()
+	}
+	for _, h := range headers {
+		if value := h.Value(ctxMeta); value != "" && outReq.Header.Get(h.Name) == "" {
+			outReq.Header.Set(h.Name, value)
+		}
+	}
+
+	injected := http.Header{}
+
+	clientSpanID := ""
+	if ctxMeta.TraceID != "" {
+		clientSpanID = NewSpanID()
+		propagators := t.config.Propagators
+		if len(propagators) == 0 {
+			propagators = DefaultPropagators()
+		}
+		sc := SpanContext{TraceID: ctxMeta.TraceID, SpanID: clientSpanID, Sampled: ctxMeta.Sampled, TraceState: ctxMeta.TraceState}
+		for _, propagator := range propagators {
+			propagator.Inject(sc, injected)
+		}
+	}
+	InjectBaggage(ctx, injected)
+	for key, values := range injected {
+		if _, ok := outReq.Header[key]; !ok {
+			outReq.Header[key] = values
+		}
+	}
+
+	labels := &models.DownstreamServiceMetricsLabelValues{
+		Name:          t.serviceName(req),
+		HTTPMethod:    req.Method,
+		APIIdentifier: t.apiIdentifier(req),
+	}
+	if t.config.DownstreamMetrics != nil {
+		t.config.DownstreamMetrics.LogMetricsPre(labels)
+	}
+
+	base := t.config.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	span := cx.StartEvent(ctx, clientSpanName, map[string]interface{}{
+		"service": labels.Name,
+		"method":  req.Method,
+		"path":    req.URL.Path,
+		"span_id": clientSpanID,
+	})
+	resp, err := base.RoundTrip(outReq)
+
+	status := 0
+	var responseSize int64
+	if resp != nil {
+		status = resp.StatusCode
+		// ContentLength is -1 when unknown
+		responseSize = max(resp.ContentLength, 0)
+	}
+	span.SetAttribute("status", status)
+	if err == nil && status >= http.StatusInternalServerError {
+		span.SetSeverity(cx.SeverityWarn)
+	}
+	elapsed := span.EndWithError(err).Duration
+
+	if t.config.DownstreamMetrics != nil {
+		success := err == nil && status >= http.StatusOK && status < http.StatusMultipleChoices
+		t.config.DownstreamMetrics.LogMetricsPost(success, labels, &models.HTTPMetrics{
+			Method:                req.Method,
+			URL:                   req.URL.Path,
+			Code:                  status,
+			RequestBodySizeBytes:  max(req.ContentLength, 0),
+			ResponseBodySizeBytes: responseSize,
+			ResponseTime:          elapsed,
+		})
+	}
+
+	appMetrics := t.config.AppMetrics
+	if appMetrics == nil {
+		appMetrics = cx.GetResponseMeta(ctx).AppMetrics
+	}
+	switch {
+	case err != nil:
+		if appMetrics != nil {
+			appMetrics.LogMetrics([]string{ErrOutboundRequestFailed.Code})
+		}
+	case status >= http.StatusInternalServerError:
+		if appMetrics != nil {
+			appMetrics.LogMetrics([]string{ErrOutboundServerError.Code})
+		}
+	}
+	return resp, err
+}
+
+func (t *Transport) serviceName(req *http.Request) string {
+	if t.config.ServiceName != "" {
+		return t.config.ServiceName
+	}
+	return req.URL.Host
+}
+
+func (t *Transport) apiIdentifier(req *http.Request) string {
+	if t.config.APIIdentifier != nil {
+		return t.config.APIIdentifier(req)
+	}
+	return req.URL.Path
+}
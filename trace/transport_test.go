@@ -0,0 +1,110 @@
+package trace
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	im "github.com/piyushkumar96/app-monitoring/interfaces"
+	cx "github.com/piyushkumar96/common-middlewares/context"
+)
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+// newTransportTestRequest returns an outbound request whose context carries request ID, trace context and baggage.
+func newTransportTestRequest(t *testing.T) *http.Request {
+	t.Helper()
+	baggage, err := ParseBaggage("tenant=acme")
+	if err != nil {
+		t.Fatalf("ParseBaggage: %v", err)
+	}
+	ctx := context.WithValue(context.Background(), cx.CtxMetaKey, &cx.CtxMeta{
+		ReqID:   "req-ctx",
+		TraceID: testTraceID,
+		SpanID:  testParentSpanID,
+		Sampled: true,
+	})
+	ctx = context.WithValue(ctx, cx.BaggageKey, baggage)
+	return httptest.NewRequest(http.MethodPost, "http://downstream.test/items", strings.NewReader("body")).WithContext(ctx)
+}
+
+func TestTransportKeepsCallerHeaders(t *testing.T) {
+	tests := []struct {
+		name   string
+		header map[string]string
+		want   map[string]string
+	}{
+		{name: "context values", want: map[string]string{
+			HeaderRequestID: "req-ctx",
+			HeaderBaggage:   "tenant=acme",
+		}},
+		{name: "caller values", header: map[string]string{
+			HeaderRequestID:   "req-caller",
+			HeaderTraceParent: "00-" + testTraceID + "-1111111111111111-00",
+			HeaderBaggage:     "tenant=caller",
+		}, want: map[string]string{
+			HeaderRequestID:   "req-caller",
+			HeaderTraceParent: "00-" + testTraceID + "-1111111111111111-00",
+			HeaderBaggage:     "tenant=caller",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent http.Header
+			transport := NewTransport(&TransportConfig{
+				Propagators: []Propagator{W3CPropagator{}},
+				Base: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+					sent = req.Header
+					return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, ContentLength: 0}, nil
+				}),
+			})
+			req := newTransportTestRequest(t)
+			for key, value := range tt.header {
+				req.Header.Set(key, value)
+			}
+
+			if _, err := transport.RoundTrip(req); err != nil {
+				t.Fatalf("RoundTrip: %v", err)
+			}
+			for key, value := range tt.want {
+				if got := sent.Values(key); len(got) != 1 || got[0] != value {
+					t.Errorf("%s = %v, want [%s]", key, got, value)
+				}
+			}
+			if _, ok := tt.header[HeaderTraceParent]; !ok {
+				tp, err := ParseTraceParent(sent.Get(HeaderTraceParent))
+				if err != nil || tp.TraceID != testTraceID || tp.ParentID == testParentSpanID {
+					t.Errorf("traceparent = %q (%v), want trace %s with a fresh client span", sent.Get(HeaderTraceParent), err, testTraceID)
+				}
+			}
+		})
+	}
+}
+
+func TestTransportClampsUnknownSizes(t *testing.T) {
+	downstreamMetrics := im.NewMockDownstreamServiceMetrics()
+	transport := NewTransport(&TransportConfig{
+		DownstreamMetrics: downstreamMetrics,
+		Base: roundTripperFunc(func(*http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("streamed")), ContentLength: -1}, nil
+		}),
+	})
+	req := newTransportTestRequest(t)
+	req.ContentLength = -1
+
+	if _, err := transport.RoundTrip(req); err != nil {
+		t.Fatalf("RoundTrip: %v", err)
+	}
+	metrics := downstreamMetrics.LogMetricsPostHTTPMetrics
+	if metrics == nil {
+		t.Fatal("downstream metrics not recorded")
+	}
+	if metrics.RequestBodySizeBytes != 0 || metrics.ResponseBodySizeBytes != 0 {
+		t.Errorf("sizes = %d/%d, want 0/0 for unknown lengths", metrics.RequestBodySizeBytes, metrics.ResponseBodySizeBytes)
+	}
+}